@@ -1,8 +1,6 @@
 package cmd
 
 import (
-	"github.com/vedantprajapati/Grove/internal/manager"
-
 	"github.com/spf13/cobra"
 )
 
@@ -19,10 +17,11 @@ Examples:
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) == 2 {
 			// Assume "gr add [set] [feature]"
-			mgr, err := manager.NewManager()
+			mgr, err := newManager()
 			if err != nil {
 				return err
 			}
+			applyJobFlags(mgr)
 			return mgr.CreateFeature(args[0], args[1])
 		}
 		return cmd.Help()
@@ -37,7 +36,7 @@ var addSetCmd = &cobra.Command{
 		name := args[0]
 		repos := args[1:]
 
-		mgr, err := manager.NewManager()
+		mgr, err := newManager()
 		if err != nil {
 			return err
 		}
@@ -50,10 +49,11 @@ var addFeatureCmd = &cobra.Command{
 	Short: "Create a new feature worktree",
 	Args:  cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		mgr, err := manager.NewManager()
+		mgr, err := newManager()
 		if err != nil {
 			return err
 		}
+		applyJobFlags(mgr)
 		return mgr.CreateFeature(args[0], args[1])
 	},
 }
@@ -62,4 +62,6 @@ func init() {
 	rootCmd.AddCommand(addCmd)
 	addCmd.AddCommand(addSetCmd)
 	addCmd.AddCommand(addFeatureCmd)
+	addJobFlags(addCmd)
+	addJobFlags(addFeatureCmd)
 }
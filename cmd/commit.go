@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/vedantprajapati/Grove/internal/manager"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+)
+
+var (
+	commitMessage     string
+	commitAll         bool
+	commitTrailer     bool
+	commitInteractive bool
+)
+
+var commitCmd = &cobra.Command{
+	Use:   "commit [feature] -m message [-- pathspec...]",
+	Short: "Commit with one message in every dirty repository of a feature",
+	Long: `Stage and commit with the same message in every repository of a feature
+that has uncommitted changes, then print the new commit of each repo.
+
+Without -a or pathspecs, only what is already staged is committed. -a stages
+modified and deleted tracked files; pathspecs stage matching files, including
+untracked ones, in every repo where they match.
+
+--trailer adds "Grove-Feature: <feature>" to each message so the sibling
+commits can be found again (git log --grep "Grove-Feature: my-feature").
+--interactive shows each repo's staged diff and asks before committing; a
+declined repo's index is left as it was before gr commit ran.
+
+Examples:
+  gr commit my-feature -a -m "Rename user id field"
+  gr commit my-feature -m "Update API client" --trailer -- 'src/client/*'
+  gr commit my-feature -a -m "WIP" --interactive`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		mgr, err := newManager()
+		if err != nil {
+			return err
+		}
+		applyJobFlags(mgr)
+		applyFilterFlags(mgr)
+
+		opts := manager.CommitOptions{
+			Message:   commitMessage,
+			All:       commitAll,
+			Pathspecs: args[1:],
+			Trailer:   commitTrailer,
+		}
+		if commitInteractive {
+			opts.Confirm = confirmCommit()
+		}
+
+		results, err := mgr.CommitFeature(args[0], opts)
+		if results == nil {
+			return err
+		}
+		cmd.SilenceUsage = true
+
+		switch {
+		case machineOutput():
+			if werr := writeOutput(results); werr != nil {
+				return werr
+			}
+		case plainOutput():
+			var rows [][]string
+			for _, r := range results {
+				rows = append(rows, []string{r.Repo, r.SHA, commitResultLabel(r)})
+			}
+			printPlain(rows)
+		default:
+			failStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#ff7b72"))
+			width := 0
+			for _, r := range results {
+				if len(r.Repo) > width {
+					width = len(r.Repo)
+				}
+			}
+			for _, r := range results {
+				name := fmt.Sprintf("%-*s", width, r.Repo)
+				switch {
+				case r.SHA != "":
+					fmt.Printf("  %s  %s\n", setNameStyle.Render(name), r.SHA[:7])
+				case r.Error != "":
+					fmt.Printf("  %s  %s\n", setNameStyle.Render(name), failStyle.Render("failed"))
+				default:
+					fmt.Printf("  %s  %s\n", setNameStyle.Render(name), dimStyle.Render(commitResultLabel(r)))
+				}
+			}
+		}
+		return err
+	},
+}
+
+// commitResultLabel describes a commit result in a word or two.
+func commitResultLabel(r manager.CommitResult) string {
+	switch {
+	case r.Error != "":
+		return "failed"
+	case r.Skipped != "":
+		return "skipped (" + r.Skipped + ")"
+	}
+	return "committed"
+}
+
+// confirmCommit asks on the terminal before each repo is committed. Answering
+// q skips the remaining repos.
+func confirmCommit() func(repo, diff string) bool {
+	in := bufio.NewReader(os.Stdin)
+	quit := false
+	return func(repo, diff string) bool {
+		if quit {
+			return false
+		}
+		fmt.Fprint(os.Stderr, diff)
+		for {
+			fmt.Fprintf(os.Stderr, "Commit in %s? [y,n,q] ", repo)
+			answer, err := in.ReadString('\n')
+			if err != nil {
+				quit = true
+				return false
+			}
+			switch strings.ToLower(strings.TrimSpace(answer)) {
+			case "y", "yes":
+				return true
+			case "n", "no":
+				return false
+			case "q", "quit":
+				quit = true
+				return false
+			}
+		}
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(commitCmd)
+	addJobFlags(commitCmd)
+	addFilterFlags(commitCmd)
+	commitCmd.Flags().StringVarP(&commitMessage, "message", "m", "", "Commit message (required)")
+	commitCmd.Flags().BoolVarP(&commitAll, "all", "a", false, "Stage modified and deleted tracked files first")
+	commitCmd.Flags().BoolVar(&commitTrailer, "trailer", false, "Add a \"Grove-Feature: <feature>\" trailer to each message")
+	commitCmd.Flags().BoolVarP(&commitInteractive, "interactive", "i", false, "Show each repo's diff and ask before committing")
+	commitCmd.MarkFlagRequired("message")
+}
@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/vedantprajapati/Grove/internal/config"
+
+	"github.com/spf13/cobra"
+)
+
+var configDryRun bool
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage the Grove config file",
+}
+
+var configMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Upgrade the config file to the current schema version",
+	Long: `Upgrade the config file to the schema version of this Grove. Grove also
+does this automatically the first time it loads an older file, keeping the
+original as <file>.v<N>.bak.
+
+With --dry-run, the migrations and the resulting changes are printed and the
+file is left alone.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := configPath()
+		if err != nil {
+			return err
+		}
+		plan, err := config.PlanMigration(path)
+		if os.IsNotExist(err) {
+			return fmt.Errorf("no config file at %s", path)
+		}
+		if err != nil {
+			return err
+		}
+
+		if plan.From == plan.To {
+			fmt.Printf("%s is already at config version %d.\n", path, plan.To)
+			return nil
+		}
+		fmt.Printf("%s is at config version %d; migrating to %d:\n", path, plan.From, plan.To)
+		for _, step := range plan.Steps {
+			fmt.Printf("  %s\n", step)
+		}
+
+		if configDryRun {
+			fmt.Println()
+			return printConfigDiff(plan.Before, plan.After)
+		}
+		_, err = config.LoadConfig(path)
+		return err
+	},
+}
+
+var configConvertCmd = &cobra.Command{
+	Use:   "convert [json|yaml|toml]",
+	Short: "Rewrite the config file in another format",
+	Long: `Rewrite the config file as JSON, YAML or TOML, next to the current one
+(~/.groverc, ~/.groverc.yaml or ~/.groverc.toml). The old file is kept as
+<file>.converted so it is no longer picked up.
+
+Grove keeps YAML comments when it saves the file; TOML files are rewritten
+without them.
+
+Examples:
+  gr config convert yaml
+  gr config convert toml --dry-run`,
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: []string{"json", "yaml", "toml"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, err := config.ParseFormat(args[0])
+		if err != nil {
+			return err
+		}
+		path, err := configPath()
+		if err != nil {
+			return err
+		}
+		cfg, err := config.LoadConfig(path)
+		if err != nil {
+			return err
+		}
+
+		if configDryRun {
+			data, err := cfg.Marshal(format)
+			if err != nil {
+				return err
+			}
+			_, err = os.Stdout.Write(data)
+			return err
+		}
+
+		from := cfg.Path()
+		to, err := cfg.Convert(format)
+		if err != nil {
+			return err
+		}
+		if to == from {
+			fmt.Printf("Converted %s to %s in place (the old file was backed up as %s.bak.1).\n", from, format, from)
+			return nil
+		}
+		fmt.Printf("Converted %s to %s (the old file was kept as %s.converted).\n", from, to, from)
+		return nil
+	},
+}
+
+// printConfigDiff shows how the config file would change, using git diff.
+func printConfigDiff(before, after []byte) error {
+	dir, err := os.MkdirTemp("", "grove-config-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	for name, data := range map[string][]byte{"current": before, "migrated": after} {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+			return err
+		}
+	}
+
+	color := "--no-color"
+	if stdoutIsTerminal() && !plainOutput() {
+		color = "--color=always"
+	}
+	c := exec.Command("git", "diff", "--no-index", "--no-prefix", color, "current", "migrated")
+	c.Dir = dir
+	out, err := c.Output()
+	// Exit status 1 only means the files differ.
+	if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+		err = nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to diff config: %v", err)
+	}
+	return pageOutput(string(out))
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configMigrateCmd)
+	configMigrateCmd.Flags().BoolVar(&configDryRun, "dry-run", false, "Print the changes without writing the file")
+	configMigrateCmd.Flags().BoolVar(&noPagerFlag, "no-pager", false, "Do not pipe output through a pager")
+	configCmd.AddCommand(configConvertCmd)
+	configConvertCmd.Flags().BoolVar(&configDryRun, "dry-run", false, "Print the converted config without writing it")
+}
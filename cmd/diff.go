@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/vedantprajapati/Grove/internal/manager"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+)
+
+var (
+	diffBase      bool
+	diffStat      bool
+	diffPatchFile string
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff [feature] [-- pathspec...]",
+	Short: "Show one combined diff across every repository in a feature",
+	Long: `Show the changes of every repository in a feature as one diff, with paths
+prefixed by the repo name (api/src/...).
+
+By default the diff covers uncommitted changes (staged and unstaged). With
+--base it covers everything the feature changed since the merge-base with
+its base branch, committed or not. Untracked files are not included.
+
+--patch-file writes a single patch that can be applied from the feature
+directory with 'git apply -p1' or reviewed as one file.
+
+Examples:
+  gr diff my-feature
+  gr diff my-feature --base --stat
+  gr diff my-feature --base --patch-file my-feature.patch
+  gr diff my-feature -- '*.go'`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		mgr, err := newManager()
+		if err != nil {
+			return err
+		}
+		applyJobFlags(mgr)
+		applyFilterFlags(mgr)
+
+		opts := manager.DiffOptions{Base: diffBase, Pathspecs: args[1:]}
+		// Colored patches only when they go straight to a terminal.
+		opts.Color = !machineOutput() && !plainOutput() && diffPatchFile == "" && !diffStat && stdoutIsTerminal()
+		diffs, err := mgr.DiffFeature(args[0], opts)
+		if err != nil {
+			return err
+		}
+		for _, d := range diffs {
+			if d.Error != "" {
+				fmt.Fprintf(os.Stderr, "Warning: %s: %s\n", d.Repo, d.Error)
+			}
+		}
+
+		if diffPatchFile != "" {
+			var patch strings.Builder
+			for _, d := range diffs {
+				patch.WriteString(d.Patch)
+			}
+			if err := os.WriteFile(diffPatchFile, []byte(patch.String()), 0644); err != nil {
+				return err
+			}
+			fmt.Fprintf(progressWriter(), "Wrote %s (%d repos)\n", diffPatchFile, len(diffs))
+			return nil
+		}
+
+		switch {
+		case machineOutput():
+			return writeOutput(diffs)
+		case plainOutput() || diffStat:
+			return pageOutput(diffStatText(diffs))
+		}
+
+		var out strings.Builder
+		for _, d := range diffs {
+			out.WriteString(d.Patch)
+		}
+		if out.Len() == 0 {
+			fmt.Fprintln(progressWriter(), dimStyle.Render("No changes."))
+			return nil
+		}
+		return pageOutput(out.String())
+	},
+}
+
+// diffStatText formats the changed files of every repo like 'git diff --stat'.
+func diffStatText(diffs []manager.RepoDiff) string {
+	const barWidth = 40
+
+	width, most := 0, 0
+	for _, d := range diffs {
+		for _, f := range d.Files {
+			if len(f.Path) > width {
+				width = len(f.Path)
+			}
+			if n := f.Added + f.Deleted; n > most {
+				most = n
+			}
+		}
+	}
+
+	plus := lipgloss.NewStyle().Foreground(lipgloss.Color("#2ea043"))
+	minus := lipgloss.NewStyle().Foreground(lipgloss.Color("#ff7b72"))
+
+	var b strings.Builder
+	files, added, deleted := 0, 0, 0
+	for _, d := range diffs {
+		for _, f := range d.Files {
+			files++
+			added += f.Added
+			deleted += f.Deleted
+			if f.Binary {
+				fmt.Fprintf(&b, " %-*s | Bin\n", width, f.Path)
+				continue
+			}
+			a, r := f.Added, f.Deleted
+			if most > barWidth {
+				a = (a*barWidth + most - 1) / most
+				r = (r*barWidth + most - 1) / most
+			}
+			fmt.Fprintf(&b, " %-*s | %5d %s%s\n", width, f.Path, f.Added+f.Deleted,
+				plus.Render(strings.Repeat("+", a)), minus.Render(strings.Repeat("-", r)))
+		}
+	}
+	fmt.Fprintf(&b, " %d files changed, %d insertions(+), %d deletions(-)\n", files, added, deleted)
+	return b.String()
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+	addJobFlags(diffCmd)
+	addFilterFlags(diffCmd)
+	diffCmd.Flags().BoolVar(&diffBase, "base", false, "Diff against the merge-base with the base branch instead of HEAD")
+	diffCmd.Flags().BoolVar(&diffStat, "stat", false, "Show a summary of changed files instead of the patch")
+	diffCmd.Flags().StringVar(&diffPatchFile, "patch-file", "", "Write the combined patch to this file")
+	diffCmd.Flags().BoolVar(&noPagerFlag, "no-pager", false, "Do not pipe output through a pager")
+}
@@ -2,35 +2,233 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/vedantprajapati/Grove/internal/manager"
 
+	"github.com/charmbracelet/lipgloss"
 	"github.com/spf13/cobra"
 )
 
+var (
+	execTimeout    time.Duration
+	execShell      bool
+	execNoTemplate bool
+	execGroup      bool
+	execFailFast   bool
+	execExitPolicy string
+	execRetryID    string
+)
+
 var execCmd = &cobra.Command{
-	Use:   "exec [feature] -- [command] [args...]",
+	Use:   "exec [feature | --all | --set name] -- [command] [args...]",
 	Short: "Execute a command across all repositories in a feature",
-	Args:  cobra.MinimumNArgs(2),
+	Long: `Execute a command in every repository of a feature in parallel.
+
+Output is streamed line by line with a [repo] prefix; stdout and stderr are
+kept separate. Use --group to print each repo's output as one block when it
+finishes instead.
+
+Arguments are expanded per repo as Go templates with {{.Repo}}, {{.Branch}},
+{{.Feature}}, {{.Set}}, {{.Path}} and {{.Base}}; the same values are exported
+as GROVE_REPO, GROVE_BRANCH, GROVE_FEATURE, GROVE_SET, GROVE_PATH and
+GROVE_BASE. Use --no-template to pass arguments that contain their own
+{{...}} (docker --format, jq) as given, or write {{"{{"}} for a literal {{.
+Use --sh to run the command line through your shell so pipes, && and globs
+work.
+
+Ctrl-C (SIGINT) and SIGTERM are forwarded to every running command. With
+--timeout, a repo's command is killed once it runs too long and the repo is
+marked as timed out.
+
+A summary of each repo's exit code and duration is printed at the end. The
+exit code is non-zero if any repo failed; --exit-policy changes this to
+"all" (fail only if every repo failed) or "never".
+
+With --all or --set, the command runs in every matching feature in turn,
+grouped by feature and then by repo.
+
+Every run is saved with each repo's output and exit code; list them with
+'gr runs'. Use --retry-failed <run-id> to rerun the same command in only the
+repos that failed.
+
+Examples:
+  gr exec my-feature -- git status -s
+  gr exec my-feature --group -- npm test
+  gr exec my-feature --fail-fast -- make test
+  gr exec my-feature --only 'svc-*' --exclude docs -- git pull
+  gr exec my-feature -- git log --oneline {{.Base}}..HEAD
+  gr exec my-feature --sh 'git diff --stat | tail -1'
+  gr exec my-feature --no-template -- docker ps --format '{{.Names}}'
+  gr exec --set my-stack -- git fetch
+  gr exec my-feature --timeout 5m -- npm test
+  gr exec --retry-failed 20240101-120000-1a2b`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if execRetryID != "" {
+			if len(args) > 0 {
+				return fmt.Errorf("--retry-failed reuses the saved command and takes no arguments")
+			}
+			return nil
+		}
+		return cobra.MinimumNArgs(1)(cmd, args)
+	},
 	RunE: func(cmd *cobra.Command, args []string) error {
-		mgr, err := manager.NewManager()
+		mgr, err := newManager()
 		if err != nil {
 			return err
 		}
 
-		featureName := args[0]
+		applyJobFlags(mgr)
+		opts := manager.ExecOptions{
+			Timeout:    execTimeout,
+			Group:      execGroup,
+			FailFast:   execFailFast,
+			ExitPolicy: execExitPolicy,
+			Record:     true,
+			Stdout:     progressWriter(),
+			Stderr:     os.Stderr,
+		}
+
+		if execRetryID != "" {
+			cmd.SilenceUsage = true
+			ctx, stop := signalContext()
+			defer stop()
+
+			return printExecResults(mgr.RetryFailed(ctx, execRetryID, opts))
+		}
+
+		applyFilterFlags(mgr)
+		features, rest, err := selectedFeatures(mgr, args)
+		if err != nil {
+			return err
+		}
 
 		// Everything after -- is the command
 		// If user didn't use --, cobra still passes args
 		// Let's assume the first arg is feature, the rest is command
-		command := args[1]
-		commandArgs := args[2:]
+		if len(rest) == 0 {
+			return fmt.Errorf("no command given")
+		}
+		command := rest[0]
+		commandArgs := rest[1:]
 
-		fmt.Printf("Running command in feature '%s'...\n", featureName)
-		return mgr.ExecFeature(featureName, command, commandArgs)
+		cmd.SilenceUsage = true
+		ctx, stop := signalContext()
+		defer stop()
+
+		opts.Shell = execShell
+		opts.NoTemplate = execNoTemplate
+		var results []manager.ExecResult
+		if len(features) == 1 && !allFeaturesFlag && setFeaturesFlag == "" {
+			fmt.Fprintf(opts.Stdout, "Running command in feature '%s'...\n", features[0])
+			results, err = mgr.ExecFeatureWithOptions(ctx, features[0], command, commandArgs, opts)
+		} else {
+			fmt.Fprintf(opts.Stdout, "Running command in %d features...\n", len(features))
+			results, err = mgr.ExecFeatures(ctx, features, command, commandArgs, opts)
+		}
+		return printExecResults(results, err)
 	},
 }
 
+// printExecResults prints the summary table, or writes the results with
+// --output json/yaml/plain, and passes err through.
+func printExecResults(results []manager.ExecResult, err error) error {
+	switch {
+	case machineOutput():
+		if results == nil {
+			results = []manager.ExecResult{}
+		}
+		if werr := writeOutput(results); werr != nil {
+			return werr
+		}
+	case plainOutput():
+		var rows [][]string
+		for _, r := range results {
+			rows = append(rows, []string{r.Feature, r.Repo, fmt.Sprintf("%d", r.ExitCode),
+				r.Duration.Round(time.Millisecond).String(), execResultLabel(r)})
+		}
+		printPlain(rows)
+	case len(results) > 0:
+		printExecSummary(results)
+	}
+	return err
+}
+
+// execResultLabel names the outcome of a repo's command.
+func execResultLabel(r manager.ExecResult) string {
+	switch {
+	case r.Cancelled:
+		return "CANCELLED"
+	case r.TimedOut:
+		return "TIMEOUT"
+	case r.Skipped:
+		return "SKIPPED"
+	case r.Err != nil:
+		return "FAILED"
+	}
+	return "OK"
+}
+
+// printExecSummary prints a table of each repo's exit code and duration.
+func printExecSummary(results []manager.ExecResult) {
+	okStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#2ea043"))
+	failStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#ff7b72"))
+
+	multi := false
+	for _, r := range results {
+		if r.Feature != results[0].Feature {
+			multi = true
+			break
+		}
+	}
+	name := func(r manager.ExecResult) string {
+		if multi {
+			return r.Feature + "/" + r.Repo
+		}
+		return r.Repo
+	}
+
+	var rows []string
+	rows = append(rows, lipgloss.NewStyle().Bold(true).Render(fmt.Sprintf("  %-30s %-6s %-10s %s", "REPO", "EXIT", "DURATION", "RESULT")))
+	for _, r := range results {
+		label := execResultLabel(r)
+		result := okStyle.Render(label)
+		exit := fmt.Sprintf("%d", r.ExitCode)
+		switch {
+		case r.Cancelled, r.Skipped:
+			result = dimStyle.Render(label)
+			exit = "-"
+		case r.TimedOut:
+			result = failStyle.Render(label)
+			exit = "-"
+		case r.Err != nil:
+			result = failStyle.Render(label)
+		}
+		rows = append(rows, fmt.Sprintf("  %-30s %-6s %-10s %s",
+			name(r), exit, r.Duration.Round(time.Millisecond), result))
+	}
+
+	fmt.Println(headerStyle.Render("Summary"))
+	fmt.Println(strings.Join(rows, "\n"))
+}
+
 func init() {
 	rootCmd.AddCommand(execCmd)
+	addJobFlags(execCmd)
+	addFilterFlags(execCmd)
+	addFeatureSelectFlags(execCmd)
+	execCmd.Flags().BoolVar(&execShell, "sh", false, "Run the command line through your shell ($SHELL -c)")
+	execCmd.Flags().BoolVar(&execNoTemplate, "no-template", false, "Pass the command line as given, without expanding {{...}}")
+	execCmd.Flags().StringVar(&execRetryID, "retry-failed", "", "Rerun a saved run's command in only the repos that failed")
+	addExecFlags(execCmd)
+}
+
+// addExecFlags registers the output and failure handling flags shared by exec and run.
+func addExecFlags(c *cobra.Command) {
+	c.Flags().DurationVar(&execTimeout, "timeout", 0, "Kill a repo's command after this long (e.g. 30s, 5m)")
+	c.Flags().BoolVar(&execGroup, "group", false, "Print each repo's output as one block when it finishes")
+	c.Flags().BoolVar(&execFailFast, "fail-fast", false, "Cancel the remaining repos after the first failure")
+	c.Flags().StringVar(&execExitPolicy, "exit-policy", manager.ExitPolicyAny, "When to exit non-zero: any, all or never")
 }
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/vedantprajapati/Grove/internal/config"
+	"github.com/vedantprajapati/Grove/internal/manager"
+
+	"github.com/spf13/cobra"
+)
+
+// Worker pool flags shared by commands that act on every repo in a set.
+var (
+	jobsFlag   int
+	serialFlag bool
+)
+
+func addJobFlags(c *cobra.Command) {
+	c.Flags().IntVarP(&jobsFlag, "jobs", "j", 0, "Max repos processed at once (default: config \"jobs\" or number of CPUs)")
+	c.Flags().BoolVar(&serialFlag, "serial", false, "Process repos one at a time in set order")
+}
+
+func applyJobFlags(mgr *manager.Manager) {
+	if serialFlag {
+		mgr.Jobs = 1
+	} else if jobsFlag > 0 {
+		mgr.Jobs = jobsFlag
+	}
+}
+
+// Repo selectors shared by exec, sync, status and push.
+var filterFlags manager.RepoFilter
+
+func addFilterFlags(c *cobra.Command) {
+	c.Flags().StringSliceVar(&filterFlags.Only, "only", nil, "Only act on these repos (names or globs, comma separated)")
+	c.Flags().StringSliceVar(&filterFlags.Exclude, "exclude", nil, "Skip these repos (names or globs, comma separated)")
+	c.Flags().BoolVar(&filterFlags.Dirty, "dirty", false, "Only act on repos with uncommitted changes")
+	c.Flags().BoolVar(&filterFlags.Changed, "changed", false, "Only act on repos with commits beyond their base branch")
+}
+
+func applyFilterFlags(mgr *manager.Manager) {
+	mgr.Filter = filterFlags
+}
+
+// Feature selectors shared by exec, sync and status.
+var (
+	allFeaturesFlag bool
+	setFeaturesFlag string
+)
+
+func addFeatureSelectFlags(c *cobra.Command) {
+	c.Flags().BoolVar(&allFeaturesFlag, "all", false, "Act on every feature")
+	c.Flags().StringVar(&setFeaturesFlag, "set", "", "Act on every feature in this set")
+}
+
+// selectedFeatures resolves the features a command acts on: every feature for
+// --all, those of a set for --set, otherwise the feature named by the first
+// argument. The remaining arguments are returned.
+func selectedFeatures(mgr *manager.Manager, args []string) ([]string, []string, error) {
+	if allFeaturesFlag && setFeaturesFlag != "" {
+		return nil, nil, fmt.Errorf("--all and --set cannot be used together")
+	}
+	if allFeaturesFlag || setFeaturesFlag != "" {
+		names, err := mgr.SelectFeatures(setFeaturesFlag)
+		return names, args, err
+	}
+	if len(args) == 0 {
+		return nil, nil, fmt.Errorf("requires a feature name, --all or --set")
+	}
+	return args[:1], args[1:], nil
+}
+
+// Global path overrides. Each falls back to its GROVE_* environment variable.
+var (
+	configFlag string
+	rootFlag   string
+	cacheFlag  string
+)
+
+func addPathFlags(c *cobra.Command) {
+	c.PersistentFlags().StringVar(&configFlag, "config", "", "Config file (default: $GROVE_CONFIG, ~/.groverc or $XDG_CONFIG_HOME/grove/config)")
+	c.PersistentFlags().StringVar(&rootFlag, "root", "", "Directory to create features in, overriding root_dir (default: $GROVE_ROOT_DIR)")
+	c.PersistentFlags().StringVar(&cacheFlag, "cache", "", "Bare repo cache (default: $GROVE_CACHE_DIR, ~/.grove/cache or $XDG_CACHE_HOME/grove)")
+}
+
+// newManager creates the Manager for a command from the path flags, with the
+// project config nearest to the working directory layered in.
+func newManager() (*manager.Manager, error) {
+	wd, _ := os.Getwd()
+	return manager.NewManagerWithOptions(manager.Options{
+		ConfigPath: configFlag,
+		RootDir:    rootFlag,
+		CacheDir:   cacheFlag,
+		ProjectDir: wd,
+	})
+}
+
+// configPath returns the config file commands act on.
+func configPath() (string, error) {
+	if configFlag != "" {
+		return configFlag, nil
+	}
+	return config.GetDefaultPath()
+}
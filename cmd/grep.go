@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/vedantprajapati/Grove/internal/manager"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+)
+
+var grepOpts manager.GrepOptions
+
+var grepCmd = &cobra.Command{
+	Use:   "grep [feature] [pattern] [-- pathspec...]",
+	Short: "Search every repository in a feature with git grep",
+	Long: `Run git grep in every repository of a feature in parallel and merge the
+results, with paths prefixed by the repo name (api/src/...). Only tracked
+files are searched.
+
+Like grep, the command exits with status 1 when nothing matches and 2 when
+git grep failed in any repo, even if others matched.
+
+Examples:
+  gr grep my-feature 'GetUser('
+  gr grep my-feature -w -i userid -- '*.go'
+  gr grep my-feature --count TODO
+  gr grep my-feature 'GetUser(' -o json`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		mgr, err := newManager()
+		if err != nil {
+			return err
+		}
+		applyJobFlags(mgr)
+		applyFilterFlags(mgr)
+
+		grepOpts.Pathspecs = args[2:]
+		files, failed, err := mgr.GrepFeature(args[0], args[1], grepOpts)
+		if err != nil {
+			return err
+		}
+		printRepoWarnings(failed)
+
+		switch {
+		case machineOutput():
+			if files == nil {
+				files = []manager.GrepFile{}
+			}
+			err = writeOutput(files)
+		case plainOutput():
+			var rows [][]string
+			for _, f := range files {
+				if grepOpts.Count {
+					rows = append(rows, []string{f.Path, strconv.Itoa(f.Count)})
+				}
+				for _, mt := range f.Matches {
+					rows = append(rows, []string{f.Path, strconv.Itoa(mt.Line), mt.Text})
+				}
+			}
+			printPlain(rows)
+		default:
+			err = pageOutput(grepText(files))
+		}
+		if err != nil {
+			return err
+		}
+
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+		switch {
+		case len(failed) > 0:
+			return &exitCodeError{Code: 2}
+		case len(files) == 0:
+			return &exitCodeError{Code: 1}
+		}
+		return nil
+	},
+}
+
+// grepText formats matches like git grep: path:line:text, or path:count.
+func grepText(files []manager.GrepFile) string {
+	pathStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#a371f7"))
+	lineStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#2ea043"))
+	sep := dimStyle.Render(":")
+
+	var b strings.Builder
+	for _, f := range files {
+		path := pathStyle.Render(f.Path)
+		if len(f.Matches) == 0 {
+			fmt.Fprintf(&b, "%s%s%d\n", path, sep, f.Count)
+			continue
+		}
+		for _, mt := range f.Matches {
+			fmt.Fprintf(&b, "%s%s%s%s%s\n", path, sep, lineStyle.Render(strconv.Itoa(mt.Line)), sep, mt.Text)
+		}
+	}
+	return b.String()
+}
+
+func init() {
+	rootCmd.AddCommand(grepCmd)
+	addJobFlags(grepCmd)
+	addFilterFlags(grepCmd)
+	grepCmd.Flags().BoolVarP(&grepOpts.IgnoreCase, "ignore-case", "i", false, "Ignore case differences")
+	grepCmd.Flags().BoolVarP(&grepOpts.WordRegexp, "word-regexp", "w", false, "Match the pattern only at word boundaries")
+	grepCmd.Flags().BoolVarP(&grepOpts.Count, "count", "c", false, "Show the number of matching lines per file")
+	grepCmd.Flags().BoolVar(&noPagerFlag, "no-pager", false, "Do not pipe output through a pager")
+}
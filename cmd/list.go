@@ -2,9 +2,11 @@ package cmd
 
 import (
 	"fmt"
+	"github.com/vedantprajapati/Grove/internal/config"
 	"github.com/vedantprajapati/Grove/internal/manager"
 	"path/filepath"
 	"runtime"
+	"sort"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/spf13/cobra"
@@ -42,13 +44,17 @@ var listCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all sets and active features",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		mgr, err := manager.NewManager()
+		mgr, err := newManager()
 		if err != nil {
 			return err
 		}
 
+		if machineOutput() || plainOutput() {
+			return writeList(mgr)
+		}
+
 		fmt.Println(headerStyle.Render("🌳 Grove Status"))
-		fmt.Printf("%s %s\n\n", dimStyle.Render("Root directory:"), mgr.Config.RootDir)
+		fmt.Printf("%s %s\n\n", dimStyle.Render("Root directory:"), mgr.Root())
 
 		// Sets Section
 		fmt.Println(lipgloss.NewStyle().Bold(true).Underline(true).Render("Sets"))
@@ -95,6 +101,49 @@ var listCmd = &cobra.Command{
 	},
 }
 
+// namedSet and namedFeature add the map key to config entries for --output.
+type namedSet struct {
+	Name string `json:"name"`
+	config.Set
+}
+
+type namedFeature struct {
+	Name string `json:"name"`
+	config.Feature
+}
+
+type listOutput struct {
+	RootDir  string         `json:"root_dir"`
+	Sets     []namedSet     `json:"sets"`
+	Features []namedFeature `json:"features"`
+}
+
+// writeList writes sets and features sorted by name with --output json/yaml/plain.
+func writeList(mgr *manager.Manager) error {
+	out := listOutput{RootDir: mgr.Root(), Sets: []namedSet{}, Features: []namedFeature{}}
+	for name, set := range mgr.Config.Sets {
+		out.Sets = append(out.Sets, namedSet{name, set})
+	}
+	for name, feat := range mgr.Config.Features {
+		out.Features = append(out.Features, namedFeature{name, feat})
+	}
+	sort.Slice(out.Sets, func(i, j int) bool { return out.Sets[i].Name < out.Sets[j].Name })
+	sort.Slice(out.Features, func(i, j int) bool { return out.Features[i].Name < out.Features[j].Name })
+
+	if machineOutput() {
+		return writeOutput(out)
+	}
+	var rows [][]string
+	for _, s := range out.Sets {
+		rows = append(rows, []string{"set", s.Name, fmt.Sprintf("%d", len(s.Repos)), s.SkillsDir})
+	}
+	for _, f := range out.Features {
+		rows = append(rows, []string{"feature", f.Name, f.Set, f.Path})
+	}
+	printPlain(rows)
+	return nil
+}
+
 func init() {
 	rootCmd.AddCommand(listCmd)
 }
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vedantprajapati/Grove/internal/manager"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+)
+
+var logOpts manager.LogOptions
+
+var logCmd = &cobra.Command{
+	Use:   "log [feature]",
+	Short: "Show the commits of every repository in a feature as one history",
+	Long: `Merge the commits of every repository's feature branch into one list,
+newest first, each tagged with its repo. By default only commits made since
+the feature left its base branch are shown; --full includes older history.
+
+Examples:
+  gr log my-feature
+  gr log my-feature --since "1 week ago" --author alice
+  gr log my-feature --grep JIRA-123 -o json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		mgr, err := newManager()
+		if err != nil {
+			return err
+		}
+		applyJobFlags(mgr)
+		applyFilterFlags(mgr)
+
+		entries, failed, err := mgr.LogFeature(args[0], logOpts)
+		if err != nil {
+			return err
+		}
+		printRepoWarnings(failed)
+
+		switch {
+		case machineOutput():
+			if entries == nil {
+				entries = []manager.LogEntry{}
+			}
+			return writeOutput(entries)
+		case plainOutput():
+			var rows [][]string
+			for _, e := range entries {
+				rows = append(rows, []string{e.Time.Format(time.RFC3339), e.Repo, e.SHA, e.Author, e.Subject})
+			}
+			printPlain(rows)
+			return nil
+		}
+
+		if len(entries) == 0 {
+			fmt.Println(dimStyle.Render("No commits."))
+			return nil
+		}
+
+		width := 0
+		for _, e := range entries {
+			if len(e.Repo) > width {
+				width = len(e.Repo)
+			}
+		}
+		shaStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#d29922"))
+
+		var b strings.Builder
+		for _, e := range entries {
+			fmt.Fprintf(&b, "%s %s %s %s %s\n",
+				dimStyle.Render(e.Time.Format("2006-01-02 15:04")),
+				setNameStyle.Render(fmt.Sprintf("%-*s", width, e.Repo)),
+				shaStyle.Render(e.SHA[:7]),
+				e.Subject,
+				dimStyle.Render("("+e.Author+")"))
+		}
+		return pageOutput(b.String())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(logCmd)
+	addJobFlags(logCmd)
+	addFilterFlags(logCmd)
+	logCmd.Flags().BoolVar(&logOpts.Full, "full", false, "Include commits from before the feature left its base branch")
+	logCmd.Flags().StringVar(&logOpts.Since, "since", "", "Only commits after this date (e.g. \"2 weeks ago\")")
+	logCmd.Flags().StringVar(&logOpts.Author, "author", "", "Only commits by authors matching this pattern")
+	logCmd.Flags().StringVar(&logOpts.Grep, "grep", "", "Only commits whose message matches this pattern")
+	logCmd.Flags().IntVarP(&logOpts.Limit, "max-count", "n", 0, "Show at most this many commits")
+	logCmd.Flags().BoolVar(&noPagerFlag, "no-pager", false, "Do not pipe output through a pager")
+}
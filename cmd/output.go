@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/vedantprajapati/Grove/internal/config"
+	"github.com/vedantprajapati/Grove/internal/git"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-isatty"
+	"github.com/muesli/termenv"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats accepted by --output.
+const (
+	outputTable = "table"
+	outputPlain = "plain"
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+)
+
+// Global output flags.
+var (
+	outputFlag string
+	formatFlag string
+)
+
+func addOutputFlags(c *cobra.Command) {
+	c.PersistentFlags().StringVarP(&outputFlag, "output", "o", outputTable, "Output format: table, plain, json or yaml")
+	c.PersistentFlags().StringVar(&formatFlag, "format", "", "Go template applied to each result, using Go field names (e.g. '{{.Repo}} {{.OK}}' for sync)")
+}
+
+// setupOutput validates the output flags and turns colors off when stdout is
+// not a terminal, NO_COLOR is set or plain output was asked for.
+func setupOutput() error {
+	switch outputFlag {
+	case outputTable, outputPlain, outputJSON, outputYAML:
+	default:
+		return fmt.Errorf("invalid --output '%s' (want table, plain, json or yaml)", outputFlag)
+	}
+
+	_, noColor := os.LookupEnv("NO_COLOR")
+	tty := isatty.IsTerminal(os.Stdout.Fd()) || isatty.IsCygwinTerminal(os.Stdout.Fd())
+	if !tty || noColor || outputFlag == outputPlain {
+		lipgloss.SetColorProfile(termenv.Ascii)
+	}
+	git.Out = progressWriter()
+	return nil
+}
+
+// machineOutput reports whether results should be written as data instead of
+// the styled tables. Progress messages should then go to stderr.
+func machineOutput() bool {
+	return formatFlag != "" || outputFlag == outputJSON || outputFlag == outputYAML
+}
+
+// plainOutput reports whether results should be written as tab-separated lines.
+func plainOutput() bool {
+	return !machineOutput() && outputFlag == outputPlain
+}
+
+// progressWriter is where commands print progress: stderr when stdout carries
+// data or plain lines.
+func progressWriter() io.Writer {
+	if machineOutput() || outputFlag == outputPlain {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+// writeOutput writes v to stdout as JSON, YAML or through the --format
+// template. With --format, a slice is rendered one element per line.
+func writeOutput(v interface{}) error {
+	if formatFlag != "" {
+		return writeTemplate(os.Stdout, formatFlag, v)
+	}
+
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	if outputFlag == outputYAML {
+		if data, err = jsonToYAML(data); err != nil {
+			return err
+		}
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	_, err = fmt.Fprintln(os.Stdout, string(data))
+	return err
+}
+
+func writeTemplate(w io.Writer, text string, v interface{}) error {
+	tmpl, err := template.New("format").Funcs(template.FuncMap{
+		"json": func(v interface{}) (string, error) {
+			data, err := json.Marshal(v)
+			return string(data), err
+		},
+		"join": strings.Join,
+	}).Parse(text)
+	if err != nil {
+		return fmt.Errorf("invalid --format: %v", err)
+	}
+
+	items := []interface{}{v}
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Slice {
+		items = items[:0]
+		for i := 0; i < rv.Len(); i++ {
+			items = append(items, rv.Index(i).Interface())
+		}
+	}
+	for _, item := range items {
+		if err := tmpl.Execute(w, item); err != nil {
+			return err
+		}
+		fmt.Fprintln(w)
+	}
+	return nil
+}
+
+// jsonToYAML re-encodes JSON as block-style YAML, keeping the JSON field
+// names and order so both formats share one schema.
+func jsonToYAML(data []byte) ([]byte, error) {
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, err
+	}
+	config.ClearYAMLStyle(&node)
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&node); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), enc.Close()
+}
+
+// printPlain writes rows as tab-separated lines.
+func printPlain(rows [][]string) {
+	for _, row := range rows {
+		fmt.Println(strings.Join(row, "\t"))
+	}
+}
+
+// printRepoWarnings writes the repos that failed to stderr, sorted by name.
+func printRepoWarnings(failed map[string]error) {
+	repos := make([]string, 0, len(failed))
+	for repo := range failed {
+		repos = append(repos, repo)
+	}
+	sort.Strings(repos)
+	for _, repo := range repos {
+		fmt.Fprintf(os.Stderr, "Warning: %s: %v\n", repo, failed[repo])
+	}
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+)
+
+var noPagerFlag bool
+
+// stdoutIsTerminal reports whether stdout is an interactive terminal.
+func stdoutIsTerminal() bool {
+	return isatty.IsTerminal(os.Stdout.Fd()) || isatty.IsCygwinTerminal(os.Stdout.Fd())
+}
+
+// pagerCommand returns the pager from $GROVE_PAGER or $PAGER, defaulting to
+// less. An empty result means output should not be paged.
+func pagerCommand() []string {
+	for _, env := range []string{"GROVE_PAGER", "PAGER"} {
+		if v, ok := os.LookupEnv(env); ok {
+			return strings.Fields(v)
+		}
+	}
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+	return []string{"less", "-FRX"}
+}
+
+// pageOutput prints text through the pager when stdout is a terminal, so long
+// cross-repo output can be scrolled like git's own.
+func pageOutput(text string) error {
+	pager := pagerCommand()
+	if noPagerFlag || len(pager) == 0 || pager[0] == "cat" || !stdoutIsTerminal() {
+		_, err := fmt.Print(text)
+		return err
+	}
+
+	c := exec.Command(pager[0], pager[1:]...)
+	c.Stdin = strings.NewReader(text)
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	if err := c.Run(); err != nil {
+		// Fall back to printing if the pager is missing.
+		if _, ok := err.(*exec.ExitError); !ok {
+			_, err = fmt.Print(text)
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var pushCmd = &cobra.Command{
+	Use:   "push [feature]",
+	Short: "Push the feature branch of all repositories to origin",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		mgr, err := newManager()
+		if err != nil {
+			return err
+		}
+		applyJobFlags(mgr)
+		applyFilterFlags(mgr)
+
+		ctx, stop := signalContext()
+		defer stop()
+
+		mgr.Out = progressWriter()
+
+		return printRepoResults(mgr.PushFeatureContext(ctx, args[0]))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(pushCmd)
+	addJobFlags(pushCmd)
+	addFilterFlags(pushCmd)
+}
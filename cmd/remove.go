@@ -6,24 +6,31 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var removeArchive bool
+
 var removeCmd = &cobra.Command{
 	Use:   "remove [feature-name]",
 	Short: "Remove a feature or a set",
 	Long: `Remove a feature workspace or a set definition.
 	
+With --archive, each repo's branch is kept in the cache as archive/<feature>
+first, and the feature is only removed if no repo has uncommitted changes.
+If that archive already exists, the time is appended to the new one's name.
+
 Examples:
   gr remove my-feature
+  gr remove my-feature --archive
   gr remove set my-set
 `,
 	Args: cobra.ArbitraryArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) == 1 {
 			// "gr remove feature-name"
-			mgr, err := manager.NewManager()
+			mgr, err := newManager()
 			if err != nil {
 				return err
 			}
-			return mgr.RemoveFeature(args[0])
+			return removeFeature(mgr, args[0])
 		}
 		return cmd.Help()
 	},
@@ -34,7 +41,7 @@ var removeSetCmd = &cobra.Command{
 	Short: "Remove a set definition",
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		mgr, err := manager.NewManager()
+		mgr, err := newManager()
 		if err != nil {
 			return err
 		}
@@ -47,16 +54,25 @@ var removeFeatureCmd = &cobra.Command{
 	Short: "Remove a feature workspace",
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		mgr, err := manager.NewManager()
+		mgr, err := newManager()
 		if err != nil {
 			return err
 		}
-		return mgr.RemoveFeature(args[0])
+		return removeFeature(mgr, args[0])
 	},
 }
 
+func removeFeature(mgr *manager.Manager, name string) error {
+	if removeArchive {
+		return mgr.ArchiveFeature(name)
+	}
+	return mgr.RemoveFeature(name)
+}
+
 func init() {
 	rootCmd.AddCommand(removeCmd)
 	removeCmd.AddCommand(removeSetCmd)
 	removeCmd.AddCommand(removeFeatureCmd)
+	removeCmd.Flags().BoolVar(&removeArchive, "archive", false, "Keep each repo's branch as archive/<feature> before removing")
+	removeFeatureCmd.Flags().BoolVar(&removeArchive, "archive", false, "Keep each repo's branch as archive/<feature> before removing")
 }
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"github.com/vedantprajapati/Grove/internal/manager"
 	"os"
@@ -14,6 +15,9 @@ var rootCmd = &cobra.Command{
 	Short: "Grove: Manage git worktrees across multiple repositories",
 	Long:  `Grove helps you manage development features that span multiple repositories by orchestrating git worktrees.`,
 	Args:  cobra.ArbitraryArgs,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return setupOutput()
+	},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) == 0 {
 			return cmd.Help()
@@ -22,7 +26,7 @@ var rootCmd = &cobra.Command{
 		// Shortcut: gr [feature] [tool] ...
 		featureName := args[0]
 
-		mgr, err := manager.NewManager()
+		mgr, err := newManager()
 		if err != nil {
 			return err
 		}
@@ -62,9 +66,32 @@ var rootCmd = &cobra.Command{
 	},
 }
 
+func init() {
+	addOutputFlags(rootCmd)
+	addPathFlags(rootCmd)
+}
+
+// exitCodeError makes gr exit with Code without printing anything, for
+// commands that report "no results" through their exit status like grep.
+type exitCodeError struct {
+	Code int
+}
+
+func (e *exitCodeError) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
+}
+
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
+		var exitErr *exitCodeError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.Code)
+		}
 		fmt.Fprintln(os.Stderr, err)
+		var execErr *manager.ExecError
+		if errors.As(err, &execErr) {
+			os.Exit(execErr.Code)
+		}
 		os.Exit(1)
 	}
 }
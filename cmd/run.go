@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/vedantprajapati/Grove/internal/manager"
+
+	"github.com/spf13/cobra"
+)
+
+var runCmd = &cobra.Command{
+	Use:   "run [feature] [task]",
+	Short: "Run a named task from the feature's set across its repositories",
+	Long: `Run a task defined on a set in .groverc. Each repo runs its own command
+(or the task's default) through your shell, starting as soon as the repos it
+depends on have finished successfully. Repos without a command for the task
+are skipped.
+
+Example .groverc set:
+  "tasks": {
+    "build": {
+      "command": "make build",
+      "repos": {"web": "npm run build"},
+      "depends_on": {"api": ["shared-lib"], "web": ["shared-lib"]}
+    }
+  }
+
+Examples:
+  gr run my-feature build
+  gr run my-feature test --fail-fast --jobs 4`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		mgr, err := newManager()
+		if err != nil {
+			return err
+		}
+		applyJobFlags(mgr)
+		applyFilterFlags(mgr)
+
+		ctx, stop := signalContext()
+		defer stop()
+
+		cmd.SilenceUsage = true
+		report, err := mgr.RunTask(ctx, args[0], args[1], manager.ExecOptions{
+			Timeout:    execTimeout,
+			Group:      execGroup,
+			FailFast:   execFailFast,
+			ExitPolicy: execExitPolicy,
+			Stdout:     progressWriter(),
+			Stderr:     os.Stderr,
+		})
+		if report == nil {
+			return err
+		}
+		if machineOutput() {
+			if werr := writeOutput(report); werr != nil {
+				return werr
+			}
+			return err
+		}
+		if plainOutput() {
+			return printExecResults(report.Results, err)
+		}
+
+		if len(report.Results) > 0 {
+			printExecSummary(report.Results)
+			fmt.Printf("\n%s %s\n", dimStyle.Render("Wall time:"), report.Wall.Round(time.Millisecond))
+			if len(report.CriticalPath) > 0 {
+				fmt.Printf("%s %s (%s)\n", dimStyle.Render("Critical path:"),
+					strings.Join(report.CriticalPath, " → "), report.CriticalTime.Round(time.Millisecond))
+			}
+		}
+		if len(report.Undefined) > 0 {
+			fmt.Printf("%s %s\n", dimStyle.Render("Not defined for:"), strings.Join(report.Undefined, ", "))
+		}
+		return err
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(runCmd)
+	addJobFlags(runCmd)
+	addFilterFlags(runCmd)
+	addExecFlags(runCmd)
+}
@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vedantprajapati/Grove/internal/manager"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+)
+
+var runsLimit int
+
+var runsCmd = &cobra.Command{
+	Use:   "runs",
+	Short: "List saved exec runs",
+	Long: `List recent 'gr exec' runs, most recent first. Each run records the command,
+the features it ran in and every repo's exit code, duration and output.
+Runs are kept in runs/ under Grove's data directory ($GROVE_HOME, ~/.grove
+or $XDG_DATA_HOME/grove); older ones are pruned automatically.
+
+Examples:
+  gr runs
+  gr runs show <run-id>
+  gr runs show <run-id> my-repo
+  gr exec --retry-failed <run-id>`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		mgr, err := newManager()
+		if err != nil {
+			return err
+		}
+
+		runs, err := mgr.ListRuns()
+		if err != nil {
+			return err
+		}
+		if len(runs) == 0 && !machineOutput() {
+			fmt.Println(dimStyle.Render("No saved runs. Runs are recorded by 'gr exec'."))
+			return nil
+		}
+		if runsLimit > 0 && len(runs) > runsLimit {
+			runs = runs[:runsLimit]
+		}
+		if machineOutput() {
+			if runs == nil {
+				runs = []*manager.RunRecord{}
+			}
+			return writeOutput(runs)
+		}
+		if plainOutput() {
+			var rows [][]string
+			for _, run := range runs {
+				rows = append(rows, []string{run.ID, run.Started.Format(time.RFC3339),
+					fmt.Sprintf("%d", len(run.Failed())), fmt.Sprintf("%d", len(run.Results)), run.CommandLine()})
+			}
+			printPlain(rows)
+			return nil
+		}
+
+		failStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#ff7b72"))
+		fmt.Println(lipgloss.NewStyle().Bold(true).Render(fmt.Sprintf("%-22s %-16s %-8s %-10s %s", "ID", "STARTED", "FAILED", "DURATION", "COMMAND")))
+		for _, run := range runs {
+			failed := fmt.Sprintf("%d/%d", len(run.Failed()), len(run.Results))
+			if len(run.Failed()) > 0 {
+				failed = failStyle.Render(fmt.Sprintf("%-8s", failed))
+			} else {
+				failed = fmt.Sprintf("%-8s", failed)
+			}
+			fmt.Printf("%-22s %-16s %s %-10s %s\n",
+				run.ID, run.Started.Format("2006-01-02 15:04"), failed,
+				run.Duration.Round(time.Millisecond), run.CommandLine())
+		}
+		return nil
+	},
+}
+
+var runsShowCmd = &cobra.Command{
+	Use:   "show [run-id] [repo | feature/repo]",
+	Short: "Show the results of a saved run, or one repo's output",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		mgr, err := newManager()
+		if err != nil {
+			return err
+		}
+
+		run, err := mgr.LoadRun(args[0])
+		if err != nil {
+			return err
+		}
+
+		if len(args) == 1 {
+			if machineOutput() {
+				return writeOutput(run)
+			}
+			if plainOutput() {
+				return printExecResults(runResults(run), nil)
+			}
+			fmt.Printf("%s %s\n", featureNameStyle.Render("Run "+run.ID), dimStyle.Render(run.Started.Format("2006-01-02 15:04:05")))
+			fmt.Printf("  Command:  %s\n", run.CommandLine())
+			fmt.Printf("  Features: %s\n\n", strings.Join(run.Features, ", "))
+			printExecSummary(runResults(run))
+			return nil
+		}
+
+		var matches []manager.RunRepoResult
+		for _, res := range run.Results {
+			if args[1] == res.Repo || args[1] == res.Feature+"/"+res.Repo {
+				matches = append(matches, res)
+			}
+		}
+		if len(matches) == 0 {
+			return fmt.Errorf("repo '%s' is not part of run '%s'", args[1], run.ID)
+		}
+		if len(matches) > 1 {
+			return fmt.Errorf("repo '%s' ran in several features; use feature/repo", args[1])
+		}
+
+		output, err := mgr.RunLog(run, matches[0])
+		if err != nil {
+			return fmt.Errorf("no output saved for '%s': %v", args[1], err)
+		}
+		fmt.Print(output)
+		return nil
+	},
+}
+
+// runResults converts a saved run back into exec results for printExecSummary.
+func runResults(run *manager.RunRecord) []manager.ExecResult {
+	results := make([]manager.ExecResult, len(run.Results))
+	for i, res := range run.Results {
+		results[i] = manager.ExecResult{
+			Feature:   res.Feature,
+			Repo:      res.Repo,
+			ExitCode:  res.ExitCode,
+			Duration:  res.Duration,
+			TimedOut:  res.TimedOut,
+			Cancelled: res.Cancelled,
+		}
+		if res.Failed {
+			results[i].Err = fmt.Errorf("exit status %d", res.ExitCode)
+		}
+	}
+	return results
+}
+
+func init() {
+	rootCmd.AddCommand(runsCmd)
+	runsCmd.AddCommand(runsShowCmd)
+	runsCmd.Flags().IntVarP(&runsLimit, "limit", "n", 20, "Number of runs to list (0 for all)")
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/vedantprajapati/Grove/internal/manager"
+)
+
+// signalContext returns a context that is cancelled on SIGINT or SIGTERM.
+// The signal is recorded as a manager.Interrupted cause so exec, sync and
+// push forward it to each child process group. Call stop to release the
+// signal handler.
+func signalContext() (context.Context, func()) {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		select {
+		case sig := <-sigs:
+			cancel(manager.Interrupted{Signal: sig})
+		case <-ctx.Done():
+		}
+	}()
+
+	return ctx, func() {
+		signal.Stop(sigs)
+		cancel(nil)
+	}
+}
@@ -2,7 +2,8 @@ package cmd
 
 import (
 	"fmt"
-	"github.com/vedantprajapati/Grove/internal/manager"
+
+	"github.com/vedantprajapati/Grove/internal/config"
 
 	"github.com/spf13/cobra"
 )
@@ -17,7 +18,7 @@ var skillsListCmd = &cobra.Command{
 	Short: "List configuration for skills",
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		mgr, err := manager.NewManager()
+		mgr, err := newManager()
 		if err != nil {
 			return err
 		}
@@ -38,7 +39,7 @@ var skillsSetDirCmd = &cobra.Command{
 	Short: "Set the source directory for skills",
 	Args:  cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		mgr, err := manager.NewManager()
+		mgr, err := newManager()
 		if err != nil {
 			return err
 		}
@@ -46,17 +47,19 @@ var skillsSetDirCmd = &cobra.Command{
 		setName := args[0]
 		path := args[1]
 
-		// Validate set exists
-		set, ok := mgr.Config.Sets[setName]
-		if !ok {
-			return fmt.Errorf("set '%s' not found", setName)
-		}
-
-		// Update path
-		set.SkillsDir = path
-		mgr.Config.Sets[setName] = set
+		err = mgr.Config.Update(func(cfg *config.Config) error {
+			// Validate set exists
+			set, ok := cfg.Sets[setName]
+			if !ok {
+				return fmt.Errorf("set '%s' not found", setName)
+			}
 
-		if err := mgr.SaveConfig(); err != nil {
+			// Update path
+			set.SkillsDir = path
+			cfg.Sets[setName] = set
+			return nil
+		})
+		if err != nil {
 			return err
 		}
 
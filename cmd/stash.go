@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vedantprajapati/Grove/internal/manager"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+)
+
+var (
+	stashMessage   string
+	stashUntracked bool
+)
+
+var stashCmd = &cobra.Command{
+	Use:   "stash [feature] [push|pop|list|drop] [id]",
+	Short: "Stash and restore the changes of every repository in a feature together",
+	Long: `Stash the changes of every dirty repository in a feature under one shared
+label, so they can be listed, restored and dropped as a group.
+
+  push  Stash every dirty repo (the default)
+  list  Show the feature's stash groups, newest first
+  pop   Restore a group (default: the newest) in every repo that has it
+  drop  Delete a group (default: the newest) from every repo
+
+A pop that conflicts in one repo still restores the others; the conflicted
+repos keep their stash and are reported.
+
+Examples:
+  gr stash my-feature -m "halfway through the rename"
+  gr stash my-feature list
+  gr stash my-feature pop
+  gr stash my-feature drop 20240105-142210`,
+	Args: cobra.RangeArgs(1, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		mgr, err := newManager()
+		if err != nil {
+			return err
+		}
+		applyJobFlags(mgr)
+		applyFilterFlags(mgr)
+
+		featureName, action, id := args[0], "push", ""
+		if len(args) > 1 {
+			action = args[1]
+		}
+		if len(args) > 2 {
+			id = args[2]
+		}
+		if action == "push" || action == "list" {
+			if id != "" {
+				return fmt.Errorf("'gr stash %s' takes no id", action)
+			}
+		}
+
+		cmd.SilenceUsage = true
+
+		var results []manager.StashResult
+		switch action {
+		case "push":
+			id, results, err = mgr.StashPush(featureName, stashMessage, stashUntracked)
+		case "list":
+			groups, err := mgr.StashList(featureName)
+			if err != nil {
+				return err
+			}
+			return printStashGroups(groups)
+		case "pop":
+			results, err = mgr.StashPop(featureName, id)
+		case "drop":
+			results, err = mgr.StashDrop(featureName, id)
+		default:
+			return fmt.Errorf("unknown stash action '%s' (want push, pop, list or drop)", action)
+		}
+		if results == nil {
+			return err
+		}
+
+		switch {
+		case machineOutput():
+			if werr := writeOutput(results); werr != nil {
+				return werr
+			}
+		case plainOutput():
+			var rows [][]string
+			for _, r := range results {
+				rows = append(rows, []string{r.Repo, stashResultLabel(action, r)})
+			}
+			printPlain(rows)
+		default:
+			printStashResults(action, id, results)
+		}
+		return err
+	},
+}
+
+// stashResultLabel describes what happened in one repo.
+func stashResultLabel(action string, r manager.StashResult) string {
+	switch {
+	case r.Error != "":
+		return "failed"
+	case r.Conflicted:
+		return "conflicted"
+	case r.Skipped != "":
+		return "skipped (" + r.Skipped + ")"
+	}
+	return map[string]string{"push": "stashed", "pop": "restored", "drop": "dropped"}[action]
+}
+
+func printStashResults(action, id string, results []manager.StashResult) {
+	failStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#ff7b72"))
+
+	done, width := 0, 0
+	for _, r := range results {
+		if r.Skipped == "" && r.Error == "" && !r.Conflicted {
+			done++
+		}
+		if len(r.Repo) > width {
+			width = len(r.Repo)
+		}
+	}
+	if action == "push" && done == 0 {
+		fmt.Println(dimStyle.Render("Nothing to stash."))
+		return
+	}
+
+	for _, r := range results {
+		label := stashResultLabel(action, r)
+		switch {
+		case r.Error != "" || r.Conflicted:
+			label = failStyle.Render(label)
+		case r.Skipped != "":
+			label = dimStyle.Render(label)
+		}
+		fmt.Printf("  %s  %s\n", setNameStyle.Render(fmt.Sprintf("%-*s", width, r.Repo)), label)
+	}
+	if action == "push" {
+		fmt.Printf("Stashed %d repos as %s\n", done, id)
+	}
+}
+
+func printStashGroups(groups []manager.StashGroup) error {
+	switch {
+	case machineOutput():
+		return writeOutput(groups)
+	case plainOutput():
+		var rows [][]string
+		for _, g := range groups {
+			rows = append(rows, []string{g.ID, strings.Join(g.Repos, ","), g.Message})
+		}
+		printPlain(rows)
+		return nil
+	}
+
+	if len(groups) == 0 {
+		fmt.Println(dimStyle.Render("No stashes."))
+		return nil
+	}
+	for _, g := range groups {
+		fmt.Printf("%s  %s  %s %s\n",
+			setNameStyle.Render(g.ID),
+			dimStyle.Render(formatAge(g.Created)),
+			g.Message,
+			dimStyle.Render("("+strings.Join(g.Repos, ", ")+")"))
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(stashCmd)
+	addJobFlags(stashCmd)
+	addFilterFlags(stashCmd)
+	stashCmd.Flags().StringVarP(&stashMessage, "message", "m", "", "Describe the stash (push)")
+	stashCmd.Flags().BoolVarP(&stashUntracked, "include-untracked", "u", false, "Also stash untracked files (push)")
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/vedantprajapati/Grove/internal/manager"
 
@@ -10,61 +11,271 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var (
+	statusCached bool
+	statusSort   string
+	statusStale  time.Duration
+)
+
 var statusCmd = &cobra.Command{
-	Use:   "status [feature]",
+	Use:   "status [feature | --all | --set name]",
 	Short: "Check the status of all repositories in a feature",
-	Args:  cobra.ExactArgs(1),
+	Long: `Show the branch, working tree state, sync state and last commit of every
+repository in a feature.
+
+With no feature, show a dashboard of every feature grouped by set: how many
+of its repos are dirty, ahead, behind, conflicted or stopped part way through
+a rebase or merge, and when it last saw a commit. Use --sort to order features within a set and --stale to list only
+features without commits for a while.
+
+SYNC compares the branch with its upstream and BASE with the branch the
+feature was created from; "unchanged" marks repos the feature has not
+committed to yet.
+
+The STATUS column counts staged (+), unstaged (~), untracked (?) and
+conflicted (!) files, followed by the number of stashes and any operation
+stopped part way (REBASE, MERGE, CHERRY-PICK, ...). MISSING means the
+worktree directory no longer exists.
+
+Examples:
+  gr status my-feature
+  gr status
+  gr status --sort activity
+  gr status --stale 336h`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		mgr, err := manager.NewManager()
+		mgr, err := newManager()
 		if err != nil {
 			return err
 		}
 
-		featureName := args[0]
-		statuses, err := mgr.GetFeatureStatus(featureName)
+		applyFilterFlags(mgr)
+		if len(args) == 0 && !allFeaturesFlag && setFeaturesFlag == "" {
+			return printDashboard(mgr)
+		}
+		features, _, err := selectedFeatures(mgr, args)
 		if err != nil {
 			return err
 		}
 
-		fmt.Println(headerStyle.Render(fmt.Sprintf("📊 Feature Status: %s", featureName)))
+		var report []featureStatus
+		for _, featureName := range features {
+			fs, err := loadFeatureStatus(mgr, featureName)
+			if err != nil {
+				return err
+			}
+			report = append(report, fs)
+		}
 
-		t := lipgloss.NewStyle().
-			Border(lipgloss.NormalBorder()).
-			BorderForeground(lipgloss.Color("#30363d")).
-			Padding(1)
+		switch {
+		case machineOutput():
+			return writeOutput(report)
+		case plainOutput():
+			var rows [][]string
+			for _, fs := range report {
+				for _, s := range fs.Repos {
+					rows = append(rows, []string{fs.Feature, s.Name, s.Branch, s.ChangesLabel(), s.SyncLabel(), s.BaseLabel(), commitLabel(s)})
+				}
+			}
+			printPlain(rows)
+		default:
+			for _, fs := range report {
+				printFeatureStatus(fs)
+			}
+		}
+		return nil
+	},
+}
 
-		var rows []string
-		// Header
-		rows = append(rows, lipgloss.NewStyle().Bold(true).Render("  REPO           BRANCH       STATUS       SYNC"))
+// printDashboard prints one row per feature, grouped by set.
+func printDashboard(mgr *manager.Manager) error {
+	// Check --sort before spending time on git.
+	if err := manager.SortSummaries(nil, statusSort); err != nil {
+		return err
+	}
+	names, err := mgr.SelectFeatures("")
+	if err != nil {
+		return err
+	}
+	sums := []manager.FeatureSummary{}
+	for _, name := range names {
+		fs, err := loadFeatureStatus(mgr, name)
+		sum := mgr.SummarizeFeature(name, fs.Repos)
+		if err != nil {
+			sum.Error = err.Error()
+		}
+		sums = append(sums, sum)
+	}
+	if err := manager.SortSummaries(sums, statusSort); err != nil {
+		return err
+	}
+	if statusStale > 0 {
+		sums = manager.StaleSummaries(sums, statusStale)
+	}
 
-		for _, s := range statuses {
-			cleanStatus := lipgloss.NewStyle().Foreground(lipgloss.Color("#2ea043")).Render("CLEAN")
-			if s.IsDirty {
-				cleanStatus = lipgloss.NewStyle().Foreground(lipgloss.Color("#ff7b72")).Render("DIRTY")
+	switch {
+	case machineOutput():
+		if sums == nil {
+			sums = []manager.FeatureSummary{}
+		}
+		return writeOutput(sums)
+	case plainOutput():
+		var rows [][]string
+		for _, s := range sums {
+			activity := ""
+			if !s.LastActivity.IsZero() {
+				activity = s.LastActivity.Format(time.RFC3339)
 			}
+			rows = append(rows, []string{s.Set, s.Feature, fmt.Sprint(s.Repos), fmt.Sprint(s.Changed), fmt.Sprint(s.Dirty),
+				fmt.Sprint(s.Ahead), fmt.Sprint(s.Behind), fmt.Sprint(s.Conflicted), fmt.Sprint(s.InProgress), activity})
+		}
+		printPlain(rows)
+		return nil
+	}
+
+	fmt.Println(headerStyle.Render("🌳 Feature Dashboard"))
+	if len(sums) == 0 {
+		if statusStale > 0 {
+			fmt.Println(dimStyle.Render(fmt.Sprintf("  No features without commits in the last %s.", statusStale)))
+		} else {
+			fmt.Println(dimStyle.Render("  No active features. Use 'gr add feature' to start work."))
+		}
+		return nil
+	}
+
+	warnStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#ff7b72"))
+	count := func(n int) string {
+		cell := fmt.Sprintf("%-8d", n)
+		if n == 0 {
+			return dimStyle.Render(cell)
+		}
+		return warnStyle.Render(cell)
+	}
 
-			// Format ABC (Ahead/Behind Count) e.g. "0	0"
-			syncStatus := s.ABC
-			parts := strings.Split(s.ABC, "\t")
-			if len(parts) == 2 {
-				ahead := parts[0]
-				behind := parts[1]
-				syncStatus = fmt.Sprintf("↑%s ↓%s", ahead, behind)
+	var cards []string
+	for i := 0; i < len(sums); {
+		set := sums[i].Set
+		rows := []string{
+			setNameStyle.Render(set),
+			lipgloss.NewStyle().Bold(true).Render(fmt.Sprintf("%-22s %-6s %-8s %-8s %-8s %-8s %-8s %-8s %s",
+				"FEATURE", "REPOS", "CHANGED", "DIRTY", "AHEAD", "BEHIND", "CONFLICT", "STOPPED", "LAST ACTIVITY")),
+		}
+		for ; i < len(sums) && sums[i].Set == set; i++ {
+			s := sums[i]
+			if s.Error != "" {
+				rows = append(rows, fmt.Sprintf("%s %s", featureNameStyle.Render(fmt.Sprintf("%-22s", s.Feature)), warnStyle.Render(s.Error)))
+				continue
+			}
+			activity := "never"
+			if !s.LastActivity.IsZero() {
+				activity = formatAge(s.LastActivity)
+			}
+			if s.Missing > 0 {
+				activity += warnStyle.Render(fmt.Sprintf(" (%d missing)", s.Missing))
 			}
+			rows = append(rows, fmt.Sprintf("%s %-6d %-8d %s %s %s %s %s %s",
+				featureNameStyle.Render(fmt.Sprintf("%-22s", s.Feature)), s.Repos, s.Changed,
+				count(s.Dirty), count(s.Ahead), count(s.Behind), count(s.Conflicted), count(s.InProgress), activity))
+		}
+		cards = append(cards, cardStyle.Render(strings.Join(rows, "\n")))
+	}
+	fmt.Println(lipgloss.JoinVertical(lipgloss.Left, cards...))
+	return nil
+}
+
+// featureStatus is the status of every selected repo in a feature, as
+// printed by 'gr status' and written by --output json/yaml.
+type featureStatus struct {
+	Feature  string               `json:"feature"`
+	Set      string               `json:"set"`
+	Path     string               `json:"path"`
+	CachedAt *time.Time           `json:"cached_at,omitempty"` // Set with --cached
+	Repos    []manager.RepoStatus `json:"repos"`
+}
+
+func loadFeatureStatus(mgr *manager.Manager, featureName string) (featureStatus, error) {
+	feat := mgr.Config.Features[featureName]
+	fs := featureStatus{Feature: featureName, Set: feat.Set, Path: feat.Path}
 
-			rows = append(rows, fmt.Sprintf("  %-14s %-12s %-12s %s",
-				setNameStyle.Render(s.Name),
-				featureNameStyle.Render(s.Branch),
-				cleanStatus,
-				syncStatus))
+	var err error
+	if statusCached {
+		var updated time.Time
+		fs.Repos, updated, err = mgr.CachedFeatureStatus(featureName)
+		fs.CachedAt = &updated
+	} else {
+		fs.Repos, err = mgr.GetFeatureStatus(featureName)
+	}
+	return fs, err
+}
+
+// commitLabel describes the last commit, e.g. "Fix login (Ada, 3h ago)".
+func commitLabel(s manager.RepoStatus) string {
+	if s.LastCommit == nil {
+		return "-"
+	}
+	subject := s.LastCommit.Subject
+	if len(subject) > 40 {
+		subject = subject[:37] + "..."
+	}
+	return fmt.Sprintf("%s (%s, %s)", subject, s.LastCommit.Author, formatAge(s.LastCommit.Time))
+}
+
+// formatAge returns how long ago t was in the largest whole unit, e.g. "3h ago".
+func formatAge(t time.Time) string {
+	d := time.Since(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	case d < 14*24*time.Hour:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	}
+	return fmt.Sprintf("%dw ago", int(d.Hours()/(24*7)))
+}
+
+// printFeatureStatus prints the status table for one feature.
+func printFeatureStatus(fs featureStatus) {
+	fmt.Println(headerStyle.Render(fmt.Sprintf("📊 Feature Status: %s", fs.Feature)))
+	if fs.CachedAt != nil {
+		fmt.Println(dimStyle.Render(fmt.Sprintf("Cached %s ago", time.Since(*fs.CachedAt).Round(time.Second))))
+	}
+
+	t := lipgloss.NewStyle().
+		Border(lipgloss.NormalBorder()).
+		BorderForeground(lipgloss.Color("#30363d")).
+		Padding(1)
+
+	var rows []string
+	// Header
+	rows = append(rows, lipgloss.NewStyle().Bold(true).Render(fmt.Sprintf("  %-14s %-12s %-24s %-12s %-22s %s", "REPO", "BRANCH", "STATUS", "SYNC", "BASE", "LAST COMMIT")))
+
+	for _, s := range fs.Repos {
+		color := "#2ea043"
+		if s.IsDirty || s.Missing || s.Error != "" || s.Operation != "" {
+			color = "#ff7b72"
 		}
+		changes := lipgloss.NewStyle().Foreground(lipgloss.Color(color)).Render(fmt.Sprintf("%-24s", s.ChangesLabel()))
 
-		fmt.Println(t.Render(strings.Join(rows, "\n")))
+		rows = append(rows, fmt.Sprintf("  %s %s %s %-12s %-22s %s",
+			setNameStyle.Render(fmt.Sprintf("%-14s", s.Name)),
+			featureNameStyle.Render(fmt.Sprintf("%-12s", s.Branch)),
+			changes,
+			s.SyncLabel(),
+			s.BaseLabel(),
+			dimStyle.Render(commitLabel(s))))
+	}
 
-		return nil
-	},
+	fmt.Println(t.Render(strings.Join(rows, "\n")))
 }
 
 func init() {
 	rootCmd.AddCommand(statusCmd)
+	addFilterFlags(statusCmd)
+	addFeatureSelectFlags(statusCmd)
+	statusCmd.Flags().BoolVar(&statusCached, "cached", false, "Read status recorded by 'gr watch' instead of calling git")
+	statusCmd.Flags().StringVar(&statusSort, "sort", manager.SortByName, "Dashboard order within a set: name, activity, stale or dirty")
+	statusCmd.Flags().DurationVar(&statusStale, "stale", 0, "Dashboard: only show features without commits for this long (e.g. 336h)")
 }
@@ -3,8 +3,6 @@ package cmd
 import (
 	"fmt"
 
-	"github.com/vedantprajapati/Grove/internal/manager"
-
 	"github.com/spf13/cobra"
 )
 
@@ -20,7 +18,7 @@ Then you can use:
   gr switch my-feature`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		mgr, err := manager.NewManager()
+		mgr, err := newManager()
 		if err != nil {
 			return err
 		}
@@ -9,34 +9,75 @@ import (
 )
 
 var syncCmd = &cobra.Command{
-	Use:   "sync [feature]",
+	Use:   "sync [feature | --all | --set name]",
 	Short: "Sync all repositories in a feature with remote",
-	Args:  cobra.ExactArgs(1),
+	Args:  cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		mgr, err := manager.NewManager()
+		mgr, err := newManager()
 		if err != nil {
 			return err
 		}
 
-		featureName := args[0]
-		feat, ok := mgr.Config.Features[featureName]
-		if !ok {
-			return fmt.Errorf("feature '%s' not found", featureName)
+		applyJobFlags(mgr)
+		applyFilterFlags(mgr)
+		features, _, err := selectedFeatures(mgr, args)
+		if err != nil {
+			return err
 		}
+		ctx, stop := signalContext()
+		defer stop()
 
-		if _, ok := mgr.Config.Sets[feat.Set]; !ok {
-			return fmt.Errorf("set '%s' not found for feature", feat.Set)
-		}
+		mgr.Out = progressWriter()
+		var results []manager.RepoResult
+		if len(features) > 1 || allFeaturesFlag || setFeaturesFlag != "" {
+			fmt.Fprintf(mgr.Out, "Syncing %d features...\n", len(features))
+			results, err = mgr.SyncFeatures(ctx, features)
+		} else {
+			featureName := features[0]
+			feat, ok := mgr.Config.Features[featureName]
+			if !ok {
+				return fmt.Errorf("feature '%s' not found", featureName)
+			}
 
-		fmt.Printf("Syncing feature '%s'...\n", featureName)
-		if err := mgr.SyncFeature(featureName); err != nil {
-			return err
-		}
+			if _, ok := mgr.Config.Sets[feat.Set]; !ok {
+				return fmt.Errorf("set '%s' not found for feature", feat.Set)
+			}
 
-		return nil
+			fmt.Fprintf(mgr.Out, "Syncing feature '%s'...\n", featureName)
+			results, err = mgr.SyncFeatureContext(ctx, featureName)
+		}
+		return printRepoResults(results, err)
 	},
 }
 
+// printRepoResults writes sync or push results with --output json/yaml/plain.
+// The table output is the progress already printed, so only err is returned.
+func printRepoResults(results []manager.RepoResult, err error) error {
+	switch {
+	case machineOutput():
+		if results == nil {
+			results = []manager.RepoResult{}
+		}
+		if werr := writeOutput(results); werr != nil {
+			return werr
+		}
+	case plainOutput():
+		var rows [][]string
+		for _, r := range results {
+			state := "ok"
+			if !r.OK {
+				state = "failed"
+			}
+			rows = append(rows, []string{r.Feature, r.Repo, state})
+		}
+		printPlain(rows)
+	}
+	return err
+}
+
 func init() {
 	rootCmd.AddCommand(syncCmd)
+	addJobFlags(syncCmd)
+	addFilterFlags(syncCmd)
+	addFeatureSelectFlags(syncCmd)
 }
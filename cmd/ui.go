@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"github.com/vedantprajapati/Grove/internal/tui"
+
+	"github.com/spf13/cobra"
+)
+
+var uiCmd = &cobra.Command{
+	Use:   "ui",
+	Short: "Browse sets and features in a full-screen terminal UI",
+	Long: `Open a full-screen browser with sets and features on the left and the live
+status of the selected feature's repos on the right. Output of actions is
+streamed into the log pane at the bottom.
+
+Keys:
+  ↑/↓ or k/j   move            tab     switch between features and repos
+  s            sync feature    p       push feature
+  t            run a task      n       create a feature in the selected set
+  a            archive         d       remove (both ask for confirmation)
+  o / enter    open a shell    e       open $EDITOR (in the selected repo)
+  r            refresh         c/esc   cancel the running action
+  pgup/pgdown  scroll the log  q       quit`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		mgr, err := newManager()
+		if err != nil {
+			return err
+		}
+		return tui.Run(mgr)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(uiCmd)
+}
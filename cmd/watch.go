@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/vedantprajapati/Grove/internal/manager"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	watchInterval time.Duration
+	watchOnce     bool
+	watchOnChange string
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Periodically fetch caches and record the status of every feature",
+	Long: `Run in the foreground, fetching the bare repository caches and recomputing
+the status of every feature on an interval. Results are written to state.json
+in Grove's data directory ($GROVE_HOME, ~/.grove or $XDG_DATA_HOME/grove) so
+'gr status --cached' can read them without calling git.
+
+When a feature's base branch moves or its upstream gets new commits, the
+command given by --on-change (or "watch_command" in .groverc) is run in the
+repo with GROVE_EVENT, GROVE_FEATURE, GROVE_REPO, GROVE_PATH, GROVE_OLD_SHA
+and GROVE_NEW_SHA set.
+
+Examples:
+  gr watch
+  gr watch --interval 10m --on-change 'notify-send "$GROVE_REPO moved"'
+  gr watch --once`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		mgr, err := newManager()
+		if err != nil {
+			return err
+		}
+
+		ctx, stop := signalContext()
+		defer stop()
+
+		if !watchOnce {
+			fmt.Printf("Watching %d features every %s (Ctrl-C to stop)...\n", len(mgr.Config.Features), watchInterval)
+		}
+		return mgr.Watch(ctx, manager.WatchOptions{
+			Interval: watchInterval,
+			Once:     watchOnce,
+			OnChange: watchOnChange,
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(watchCmd)
+	watchCmd.Flags().DurationVar(&watchInterval, "interval", 5*time.Minute, "Time between refreshes")
+	watchCmd.Flags().BoolVar(&watchOnce, "once", false, "Refresh once and exit")
+	watchCmd.Flags().StringVar(&watchOnChange, "on-change", "", "Command to run when a base branch or upstream moves")
+}
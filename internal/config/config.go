@@ -1,7 +1,7 @@
 package config
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -11,43 +11,98 @@ import (
 const ConfigFileName = ".groverc"
 
 type Config struct {
-	filePath string             // Private field to store where to save
-	RootDir  string             `json:"root_dir"`
-	Sets     map[string]Set     `json:"sets"`
-	Features map[string]Feature `json:"features"`
+	filePath      string             // Private field to store where to save
+	format        Format             // Syntax of the file, kept when saving
+	project       *Project           // Project config layered over this one, if any
+	overlay       *overlay           // What the project layer changed
+	loadedVersion int                // Schema version of the file when it was read
+	onDisk        bool               // Whether the file existed when it was read
+	Version       int                `json:"version"` // Schema version; see CurrentVersion
+	RootDir       string             `json:"root_dir"`
+	Sets          map[string]Set     `json:"sets"`
+	Features      map[string]Feature `json:"features"`
+	WatchCommand  string             `json:"watch_command,omitempty"` // Run by `gr watch` when a base or upstream moves
+	Jobs          int                `json:"jobs,omitempty"`          // Default max repos processed at once
 }
 
 type Set struct {
-	Repos     []string `json:"repos"`
-	SkillsDir string   `json:"skills_dir"`
+	Repos     []string        `json:"repos"`
+	SkillsDir string          `json:"skills_dir"`
+	Tasks     map[string]Task `json:"tasks,omitempty"`
+}
+
+// Task is a named command run across the repos of a set with `gr run`.
+// Repos with neither a per-repo command nor a default are skipped.
+type Task struct {
+	Command   string              `json:"command,omitempty"`    // Default command for every repo
+	Repos     map[string]string   `json:"repos,omitempty"`      // Per-repo command, keyed by repo name
+	DependsOn map[string][]string `json:"depends_on,omitempty"` // Repo name -> repos whose task must finish first
 }
 
 type Feature struct {
-	Path string `json:"path"`
-	Set  string `json:"set"`
+	Path  string            `json:"path"`
+	Set   string            `json:"set"`
+	Bases map[string]string `json:"bases,omitempty"` // Branch each repo's worktree was created from
 }
 
-// DefaultConfig returns defaults.
-func DefaultConfig() Config {
+// DefaultConfig returns defaults. Features are created in
+// $GROVE_HOME/worktrees when GROVE_HOME is set, and otherwise in
+// ~/Documents/Grove.
+func DefaultConfig() (Config, error) {
+	var rootDir string
+	if home := os.Getenv(EnvHome); home != "" {
+		rootDir = filepath.Join(home, "worktrees")
+	} else {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return Config{}, fmt.Errorf("cannot find the home directory (set %s): %v", EnvHome, err)
+		}
+		rootDir = filepath.Join(home, "Documents", "Grove")
+	}
+
 	path, _ := GetDefaultPath()
-	home, _ := os.UserHomeDir()
 	return Config{
-		filePath: path,
-		RootDir:  filepath.Join(home, "Documents", "Grove"),
-		Sets:     make(map[string]Set),
-		Features: make(map[string]Feature),
-	}
+		filePath:      path,
+		format:        detectFormat(path, nil),
+		loadedVersion: CurrentVersion,
+		Version:       CurrentVersion,
+		RootDir:       rootDir,
+		Sets:          make(map[string]Set),
+		Features:      make(map[string]Feature),
+	}, nil
 }
 
+// GetDefaultPath returns the config file to use when none is given; see
+// DefaultPaths. Of .groverc, .groverc.yaml, .groverc.yml and .groverc.toml
+// the first that exists is used.
 func GetDefaultPath() (string, error) {
-	home, err := os.UserHomeDir()
+	paths, err := DefaultPaths()
 	if err != nil {
 		return "", err
 	}
-	return filepath.Join(home, ConfigFileName), nil
+	return paths.Config, nil
 }
 
+// findConfigFile returns the first existing variant of base with a config
+// file extension, or base itself.
+func findConfigFile(base string) string {
+	for _, ext := range []string{"", ".yaml", ".yml", ".toml"} {
+		if _, err := os.Stat(base + ext); err == nil {
+			return base + ext
+		}
+	}
+	return base
+}
+
+// Files kept next to the config file.
+const (
+	lockSuffix = ".lock"    // Held while the config is read for an update or written
+	maxBackups = 3          // Previous versions kept as <file>.bak.1 (newest) to .bak.3
+	corruptExt = ".corrupt" // A file that could not be parsed, set aside on recovery
+)
+
 // LoadConfig loads from a specific path. If path is empty, uses default.
+// A file that cannot be parsed is replaced by its newest readable backup.
 func LoadConfig(customPath string) (*Config, error) {
 	var path string
 	if customPath != "" {
@@ -60,22 +115,94 @@ func LoadConfig(customPath string) (*Config, error) {
 		}
 	}
 
-	if _, err := os.Stat(path); os.IsNotExist(err) {
-		cfg := DefaultConfig()
+	cfg, err := readConfig(path)
+	var perr *parseError
+	if errors.As(err, &perr) {
+		cfg, err = recoverLocked(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Saving an older file migrates it, keeping the original.
+	if cfg.loadedVersion < CurrentVersion {
+		if err := cfg.Update(func(*Config) error { return nil }); err != nil {
+			return nil, err
+		}
+	}
+	return cfg, nil
+}
+
+// recoverLocked takes the lock and restores a damaged config from a backup,
+// unless another process repaired it while we waited.
+func recoverLocked(path string) (*Config, error) {
+	unlock, err := lockConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	cfg, err := readConfig(path)
+	var perr *parseError
+	if errors.As(err, &perr) {
+		return recoverConfig(path, err)
+	}
+	return cfg, err
+}
+
+// readConfig parses the config file at path, or returns the defaults if it
+// does not exist.
+func readConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		cfg, err := DefaultConfig()
+		if err != nil {
+			return nil, err
+		}
 		cfg.filePath = path
+		cfg.format = detectFormat(path, nil)
 		return &cfg, nil
 	}
+	if err != nil {
+		return nil, err
+	}
+	return parseConfig(path, data)
+}
 
-	data, err := os.ReadFile(path)
+// parseError reports a config file that exists but is not valid, which is
+// the only error recovered from a backup.
+type parseError struct {
+	path string
+	err  error
+}
+
+func (e *parseError) Error() string {
+	return fmt.Sprintf("failed to parse %s: %v", e.path, e.err)
+}
+
+// parseConfig decodes a config file, migrating it to CurrentVersion.
+func parseConfig(path string, data []byte) (*Config, error) {
+	format := detectFormat(path, data)
+	raw, err := decodeRaw(path, format, data)
+	if err != nil {
+		return nil, err
+	}
+	if raw == nil {
+		raw = make(map[string]interface{})
+	}
+	version, err := migrateRaw(path, raw)
 	if err != nil {
 		return nil, err
 	}
 
 	var cfg Config
-	if err := json.Unmarshal(data, &cfg); err != nil {
+	if err := decodeStrict(path, raw, &cfg); err != nil {
 		return nil, err
 	}
 	cfg.filePath = path // Ensure we remember where to save
+	cfg.format = format
+	cfg.loadedVersion = version
+	cfg.onDisk = true
 
 	// Ensure maps are initialized if nil
 	if cfg.Sets == nil {
@@ -88,17 +215,206 @@ func LoadConfig(customPath string) (*Config, error) {
 	return &cfg, nil
 }
 
+// recoverConfig restores a config that cannot be parsed from its newest
+// readable backup, keeping the damaged file as <file>.corrupt. The caller
+// holds the lock.
+func recoverConfig(path string, parseErr error) (*Config, error) {
+	for i := 1; i <= maxBackups; i++ {
+		backup := backupPath(path, i)
+		data, err := os.ReadFile(backup)
+		if err != nil {
+			continue
+		}
+		cfg, err := parseConfig(path, data)
+		if err != nil {
+			continue
+		}
+
+		if err := os.Rename(path, path+corruptExt); err != nil {
+			return nil, err
+		}
+		if err := writeFileAtomic(path, data, 0644); err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(os.Stderr, "Warning: %v\nRestored %s from %s; the damaged file was kept as %s.\n",
+			parseErr, path, backup, path+corruptExt)
+		return cfg, nil
+	}
+	return nil, fmt.Errorf("%v (no usable backup in %s.bak.*)", parseErr, path)
+}
+
+// Save writes the config as it is in memory, replacing the file atomically.
+// Use Update for load-modify-save, so changes made by other processes since
+// the config was loaded are not lost.
 func (c *Config) Save() error {
 	if c.filePath == "" {
 		return fmt.Errorf("no config file path set")
 	}
 
-	data, err := json.MarshalIndent(c, "", "  ")
+	unlock, err := lockConfig(c.filePath)
 	if err != nil {
 		return err
 	}
+	defer unlock()
+	if err := c.checkNotRemoved(); err != nil {
+		return err
+	}
+	if err := c.checkOverlay(); err != nil {
+		return err
+	}
+	return c.write()
+}
 
-	return os.WriteFile(c.filePath, data, 0644)
+// Update applies fn to the latest config on disk and saves the result while
+// holding a lock, so concurrent gr processes don't overwrite each other's
+// changes. On success c is replaced by the saved config; if fn returns an
+// error nothing is written.
+//
+// fn is given the config reread from the file, not c: fields of c changed in
+// memory and not yet saved are dropped. Make changes inside fn, or Save first.
+// Changes to values from a project layer fail; see ApplyProject.
+func (c *Config) Update(fn func(cfg *Config) error) error {
+	if c.filePath == "" {
+		return fmt.Errorf("no config file path set")
+	}
+
+	unlock, err := lockConfig(c.filePath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	latest, err := readConfig(c.filePath)
+	var perr *parseError
+	if errors.As(err, &perr) {
+		latest, err = recoverConfig(c.filePath, err)
+	}
+	if err != nil {
+		return err
+	}
+	if err := c.checkNotRemoved(); err != nil {
+		return err
+	}
+	if c.overlay != nil {
+		latest.applyOverlay(c.overlay.path, c.project)
+	}
+	if err := fn(latest); err != nil {
+		return err
+	}
+	if err := latest.checkOverlay(); err != nil {
+		return err
+	}
+	if latest.loadedVersion < CurrentVersion {
+		if err := backupBeforeMigration(latest); err != nil {
+			return err
+		}
+	}
+	if err := latest.write(); err != nil {
+		return err
+	}
+	*c = *latest
+	return nil
+}
+
+// checkNotRemoved fails if the file c was loaded from no longer exists, for
+// example because it was converted to another format. Writing it again would
+// start a new file, which could also hide the converted copy. The caller
+// holds the lock.
+func (c *Config) checkNotRemoved() error {
+	if _, err := os.Stat(c.filePath); c.onDisk && os.IsNotExist(err) {
+		return fmt.Errorf("%s was removed or converted after it was loaded; run the command again", c.filePath)
+	}
+	return nil
+}
+
+// write rotates the backups and atomically replaces the config file. The
+// caller holds the lock.
+func (c *Config) write() error {
+	c.Version = CurrentVersion
+	c.loadedVersion = CurrentVersion
+	c.onDisk = true
+
+	previous, err := os.ReadFile(c.filePath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	data, err := c.encode(previous)
+	if err != nil {
+		return err
+	}
+	if err := rotateBackups(c.filePath, previous); err != nil {
+		return err
+	}
+	return writeFileAtomic(c.filePath, data, 0644)
+}
+
+// lockConfig takes the cross-process lock for the config file at path and
+// returns the function that releases it.
+func lockConfig(path string) (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path+lockSuffix, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+	if err := lockFile(f); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to lock %s: %v", path, err)
+	}
+	return func() {
+		unlockFile(f)
+		f.Close()
+	}, nil
+}
+
+func backupPath(path string, i int) string {
+	return fmt.Sprintf("%s.bak.%d", path, i)
+}
+
+// rotateBackups shifts <file>.bak.N up by one and copies current, the file
+// being replaced, to <file>.bak.1. A current file that cannot be parsed is not
+// backed up, so a damaged file never pushes out a good backup.
+func rotateBackups(path string, current []byte) error {
+	if current == nil {
+		return nil
+	}
+	if _, err := decodeRaw(path, detectFormat(path, current), current); err != nil {
+		return nil
+	}
+
+	for i := maxBackups - 1; i >= 1; i-- {
+		if err := os.Rename(backupPath(path, i), backupPath(path, i+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return writeFileAtomic(backupPath(path, 1), current, 0644)
+}
+
+// writeFileAtomic writes data to a temporary file in the same directory and
+// renames it over path, so readers see either the old or the new contents.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // No-op once renamed
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
 }
 
 // ExpandPath expands the tilde (~) in the path to the user's home directory.
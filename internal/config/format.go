@@ -0,0 +1,294 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Format is the syntax of a config file.
+type Format string
+
+// Config file formats. Every format decodes into the same structs; the field
+// names are the JSON ones in all of them.
+const (
+	FormatJSON Format = "json"
+	FormatYAML Format = "yaml"
+	FormatTOML Format = "toml"
+)
+
+// ParseFormat validates a format name.
+func ParseFormat(name string) (Format, error) {
+	switch f := Format(strings.ToLower(name)); f {
+	case FormatJSON, FormatYAML, FormatTOML:
+		return f, nil
+	case "yml":
+		return FormatYAML, nil
+	}
+	return "", fmt.Errorf("invalid config format '%s' (want json, yaml or toml)", name)
+}
+
+// Path returns the file the config is loaded from and saved to.
+func (c *Config) Path() string {
+	return c.filePath
+}
+
+// Format returns the syntax the config is saved in.
+func (c *Config) Format() Format {
+	return c.format
+}
+
+// PathForFormat returns where a config at path is kept when converted to
+// format: the same name with the format's extension.
+func PathForFormat(path string, format Format) string {
+	dir, base := filepath.Split(path)
+	switch ext := filepath.Ext(base); ext {
+	case ".json", ".yaml", ".yml", ".toml":
+		base = strings.TrimSuffix(base, ext)
+	}
+	if format == FormatJSON && base == ConfigFileName {
+		return filepath.Join(dir, base)
+	}
+	return filepath.Join(dir, base+"."+string(format))
+}
+
+var tomlKeyLine = regexp.MustCompile(`^[A-Za-z0-9_."-]+\s*=`)
+
+// detectFormat picks the format from the file extension, or for files such
+// as .groverc from the first line that is not blank or a comment.
+func detectFormat(path string, data []byte) Format {
+	switch filepath.Ext(path) {
+	case ".json":
+		return FormatJSON
+	case ".yaml", ".yml":
+		return FormatYAML
+	case ".toml":
+		return FormatTOML
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		switch {
+		case strings.HasPrefix(line, "{"):
+			return FormatJSON
+		case strings.HasPrefix(line, "[") || tomlKeyLine.MatchString(line):
+			return FormatTOML
+		}
+		return FormatYAML
+	}
+	return FormatJSON
+}
+
+// decodeRaw parses a config file into a generic document with JSON types
+// (float64 numbers), so migrations see the same shapes in every format.
+func decodeRaw(path string, format Format, data []byte) (map[string]interface{}, error) {
+	var raw map[string]interface{}
+	var err error
+	switch format {
+	case FormatYAML:
+		err = yaml.Unmarshal(data, &raw)
+	case FormatTOML:
+		_, err = toml.Decode(string(data), &raw)
+	default:
+		err = json.Unmarshal(data, &raw)
+	}
+	if err != nil {
+		return nil, &parseError{path: path, err: err}
+	}
+	if format == FormatJSON {
+		return raw, nil
+	}
+
+	normalized, err := json.Marshal(raw)
+	if err != nil {
+		return nil, &parseError{path: path, err: err}
+	}
+	raw = nil
+	if err := json.Unmarshal(normalized, &raw); err != nil {
+		return nil, &parseError{path: path, err: err}
+	}
+	return raw, nil
+}
+
+// encode renders the config in its format, leaving out a project layer. For
+// YAML, comments in previous (the file being replaced) are kept on the keys
+// that still exist.
+func (c *Config) encode(previous []byte) ([]byte, error) {
+	data, err := json.MarshalIndent(c.withoutOverlay(), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	switch c.format {
+	case FormatYAML:
+		var node yaml.Node
+		if err := yaml.Unmarshal(data, &node); err != nil {
+			return nil, err
+		}
+		ClearYAMLStyle(&node)
+		var old yaml.Node
+		if yaml.Unmarshal(previous, &old) == nil {
+			copyComments(&old, &node)
+		}
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(&node); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), enc.Close()
+
+	case FormatTOML:
+		var raw map[string]interface{}
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return nil, err
+		}
+		var buf bytes.Buffer
+		enc := toml.NewEncoder(&buf)
+		enc.Indent = ""
+		if err := enc.Encode(tomlValues(raw)); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+	return data, nil
+}
+
+// ClearYAMLStyle turns the flow style YAML reads from JSON into block style.
+func ClearYAMLStyle(n *yaml.Node) {
+	n.Style = 0
+	for _, c := range n.Content {
+		ClearYAMLStyle(c)
+	}
+}
+
+// copyComments copies the comments of old onto the matching nodes of n:
+// mapping values are matched by key and sequence items by position.
+func copyComments(old, n *yaml.Node) {
+	if old.HeadComment != "" || old.LineComment != "" || old.FootComment != "" {
+		n.HeadComment, n.LineComment, n.FootComment = old.HeadComment, old.LineComment, old.FootComment
+	}
+
+	switch {
+	case old.Kind == yaml.DocumentNode && n.Kind == yaml.DocumentNode,
+		old.Kind == yaml.SequenceNode && n.Kind == yaml.SequenceNode:
+		for i := 0; i < len(old.Content) && i < len(n.Content); i++ {
+			copyComments(old.Content[i], n.Content[i])
+		}
+	case old.Kind == yaml.MappingNode && n.Kind == yaml.MappingNode:
+		for i := 0; i+1 < len(old.Content); i += 2 {
+			for j := 0; j+1 < len(n.Content); j += 2 {
+				if old.Content[i].Value == n.Content[j].Value {
+					copyComments(old.Content[i], n.Content[j])
+					copyComments(old.Content[i+1], n.Content[j+1])
+					break
+				}
+			}
+		}
+	}
+}
+
+// tomlValues prepares a JSON document for TOML: nulls, which TOML cannot
+// represent, are removed and whole numbers become integers.
+func tomlValues(raw map[string]interface{}) map[string]interface{} {
+	for k, v := range raw {
+		switch v := v.(type) {
+		case nil:
+			delete(raw, k)
+		case float64:
+			if v == float64(int64(v)) {
+				raw[k] = int64(v)
+			}
+		case map[string]interface{}:
+			tomlValues(v)
+		}
+	}
+	return raw
+}
+
+// Marshal renders the config in format, as Convert would write it.
+func (c *Config) Marshal(format Format) ([]byte, error) {
+	converted := *c
+	converted.format = format
+	converted.Version = CurrentVersion
+	return converted.encode(nil)
+}
+
+// Convert rewrites the config file in format, next to the current file, and
+// keeps the old file as <file>.converted so it is no longer picked up. A file
+// converted in place, such as .groverc becoming JSON, is backed up like any
+// other write. It returns the new path.
+func (c *Config) Convert(format Format) (string, error) {
+	if c.filePath == "" {
+		return "", fmt.Errorf("no config file path set")
+	}
+	target := PathForFormat(c.filePath, format)
+	if format == c.format {
+		return "", fmt.Errorf("%s is already %s", c.filePath, format)
+	}
+
+	// Lock both files, in a fixed order, so no update of either runs
+	// while the config moves from one to the other.
+	locks := []string{c.filePath}
+	if target != c.filePath {
+		locks = append(locks, target)
+		sort.Strings(locks)
+	}
+	for _, path := range locks {
+		unlock, err := lockConfig(path)
+		if err != nil {
+			return "", err
+		}
+		defer unlock()
+	}
+
+	latest, err := readConfig(c.filePath)
+	if err != nil {
+		return "", err
+	}
+	if !latest.onDisk {
+		return "", fmt.Errorf("%s does not exist", c.filePath)
+	}
+	if target != c.filePath {
+		if _, err := os.Stat(target); err == nil {
+			return "", fmt.Errorf("%s already exists", target)
+		}
+	}
+	data, err := latest.Marshal(format)
+	if err != nil {
+		return "", err
+	}
+	if target == c.filePath {
+		previous, err := os.ReadFile(c.filePath)
+		if err != nil {
+			return "", err
+		}
+		if err := rotateBackups(c.filePath, previous); err != nil {
+			return "", err
+		}
+	}
+	if err := writeFileAtomic(target, data, 0644); err != nil {
+		return "", err
+	}
+	if target != c.filePath {
+		if err := os.Rename(c.filePath, c.filePath+".converted"); err != nil && !os.IsNotExist(err) {
+			return "", err
+		}
+	}
+
+	latest.filePath, latest.format, latest.onDisk = target, format, true
+	latest.Version, latest.loadedVersion = CurrentVersion, CurrentVersion
+	*c = *latest
+	return target, nil
+}
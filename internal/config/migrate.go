@@ -0,0 +1,120 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// CurrentVersion is the config schema version written by this Grove.
+// Files without a version field are version 0.
+var CurrentVersion = len(migrations)
+
+// migration upgrades the raw JSON of a config from version i to i+1, where i
+// is its index in migrations. Migrations work on the raw document so they can
+// change shapes the current structs can no longer decode.
+type migration struct {
+	description string
+	apply       func(raw map[string]interface{}) error
+}
+
+// migrations must only ever be appended to.
+var migrations = []migration{
+	{
+		description: "Record the schema version in the file",
+		apply:       func(raw map[string]interface{}) error { return nil },
+	},
+}
+
+// MigrationPlan describes how a config file would be upgraded.
+type MigrationPlan struct {
+	Path   string
+	From   int
+	To     int
+	Steps  []string // One description per migration applied
+	Before []byte   // The file as it is
+	After  []byte   // The file as Grove would save it
+}
+
+// PlanMigration reads the config file at path and returns the migrations it
+// needs, without changing anything.
+func PlanMigration(path string) (*MigrationPlan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := parseConfig(path, data)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Version = CurrentVersion
+	after, err := cfg.encode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &MigrationPlan{Path: path, From: cfg.loadedVersion, To: CurrentVersion, Before: data, After: after}
+	for v := cfg.loadedVersion; v < CurrentVersion; v++ {
+		plan.Steps = append(plan.Steps, fmt.Sprintf("v%d → v%d: %s", v, v+1, migrations[v].description))
+	}
+	return plan, nil
+}
+
+// migrateRaw upgrades a raw config document in place to CurrentVersion and
+// returns the version it started at.
+func migrateRaw(path string, raw map[string]interface{}) (int, error) {
+	version := 0
+	if v, ok := raw["version"]; ok {
+		n, ok := v.(float64)
+		if !ok || n < 0 || n != float64(int(n)) {
+			return 0, fmt.Errorf("invalid version %v in %s", v, path)
+		}
+		version = int(n)
+	}
+	if version > CurrentVersion {
+		return 0, fmt.Errorf("%s has config version %d, but this Grove only supports up to %d; upgrade Grove", path, version, CurrentVersion)
+	}
+
+	for v := version; v < CurrentVersion; v++ {
+		if err := migrations[v].apply(raw); err != nil {
+			return 0, fmt.Errorf("failed to migrate %s from version %d to %d: %v", path, v, v+1, err)
+		}
+	}
+	raw["version"] = CurrentVersion
+	return version, nil
+}
+
+// decodeStrict decodes a migrated document, rejecting fields the structs
+// don't know, which would otherwise be silently dropped on the next save.
+func decodeStrict(path string, raw map[string]interface{}, v interface{}) error {
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("%s: %v (Grove would drop it when saving; fix or remove it)", path, err)
+	}
+	return nil
+}
+
+// backupBeforeMigration keeps the file as it was before its first migration
+// from cfg.loadedVersion, as <file>.v<N>.bak. The caller holds the lock.
+func backupBeforeMigration(cfg *Config) error {
+	backup := fmt.Sprintf("%s.v%d.bak", cfg.filePath, cfg.loadedVersion)
+	if _, err := os.Stat(backup); err == nil {
+		return nil
+	}
+	data, err := os.ReadFile(cfg.filePath)
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(backup, data, 0644); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Migrated %s from config version %d to %d; the original was kept as %s.\n",
+		cfg.filePath, cfg.loadedVersion, CurrentVersion, backup)
+	return nil
+}
@@ -0,0 +1,85 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// Environment variables that relocate Grove's files.
+const (
+	EnvConfig   = "GROVE_CONFIG"    // Config file
+	EnvRootDir  = "GROVE_ROOT_DIR"  // Where feature worktrees are created; overrides root_dir
+	EnvCacheDir = "GROVE_CACHE_DIR" // Bare repo cache
+	EnvHome     = "GROVE_HOME"      // Directory holding config, state and cache together
+)
+
+// Paths are the locations of Grove's files.
+type Paths struct {
+	Config string // Config file
+	Data   string // State: runs, watch state, skills
+	Cache  string // Bare repo cache
+}
+
+// DefaultPaths resolves where Grove keeps its files, in order of precedence:
+//
+//   - GROVE_CONFIG and GROVE_CACHE_DIR for the config file and cache;
+//   - GROVE_HOME, holding config (with any config extension), state and cache/;
+//   - an existing ~/.groverc and ~/.grove, so current setups keep working;
+//   - on Linux, the XDG base directories: $XDG_CONFIG_HOME/grove/config,
+//     $XDG_DATA_HOME/grove and $XDG_CACHE_HOME/grove;
+//   - elsewhere, ~/.groverc and ~/.grove.
+func DefaultPaths() (Paths, error) {
+	var p Paths
+	if home := os.Getenv(EnvHome); home != "" {
+		p = Paths{
+			Config: findConfigFile(filepath.Join(home, "config")),
+			Data:   home,
+			Cache:  filepath.Join(home, "cache"),
+		}
+	} else {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return Paths{}, fmt.Errorf("cannot find the home directory (set %s): %v", EnvHome, err)
+		}
+		p = homePaths(home)
+	}
+
+	if path := os.Getenv(EnvConfig); path != "" {
+		p.Config = path
+	}
+	if dir := os.Getenv(EnvCacheDir); dir != "" {
+		p.Cache = dir
+	}
+	return p, nil
+}
+
+func homePaths(home string) Paths {
+	legacyConfig := findConfigFile(filepath.Join(home, ConfigFileName))
+	legacyData := filepath.Join(home, ".grove")
+	p := Paths{Config: legacyConfig, Data: legacyData, Cache: filepath.Join(legacyData, "cache")}
+	if runtime.GOOS != "linux" {
+		return p
+	}
+
+	xdg := func(env, fallback string) string {
+		if dir := os.Getenv(env); filepath.IsAbs(dir) {
+			return filepath.Join(dir, "grove")
+		}
+		return filepath.Join(home, fallback, "grove")
+	}
+	if !exists(legacyConfig) {
+		p.Config = findConfigFile(filepath.Join(xdg("XDG_CONFIG_HOME", ".config"), "config"))
+	}
+	if !exists(legacyData) {
+		p.Data = xdg("XDG_DATA_HOME", filepath.Join(".local", "share"))
+		p.Cache = xdg("XDG_CACHE_HOME", ".cache")
+	}
+	return p
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
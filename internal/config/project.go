@@ -0,0 +1,315 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// ProjectFileNames are the project config files looked for in the working
+// directory and its parents, in order of preference.
+var ProjectFileNames = []string{".grove.yaml", ".grove.yml", ".grove.json", ".grove.toml"}
+
+// Project is a project-local config layered over the global one, so a
+// workspace can check in its set definitions. Relative paths are resolved
+// against the directory holding the file. Features always live in the global
+// config.
+type Project struct {
+	Version      int            `json:"version,omitempty"`
+	RootDir      string         `json:"root_dir,omitempty"`
+	Sets         map[string]Set `json:"sets,omitempty"`
+	WatchCommand string         `json:"watch_command,omitempty"`
+	Jobs         int            `json:"jobs,omitempty"`
+}
+
+// overlay remembers what a project layer contributed, so that saving writes
+// only the global config.
+type overlay struct {
+	path              string
+	root, watch, jobs bool // Whether the project set root_dir, watch_command and jobs
+	globalRoot        string
+	globalWatch       string
+	globalJobs        int
+	sets              map[string]*setOverlay
+}
+
+// setOverlay is what a project layer contributed to one set.
+type setOverlay struct {
+	global    *Set     // Global definition before the layer; nil if only the project has it
+	applied   Set      // The set after applying the layer
+	repos     bool     // Whether the project set repos
+	skillsDir bool     // Whether the project set skills_dir
+	tasks     []string // Tasks the project added or replaced
+}
+
+// FindProjectFile returns the nearest project config file in dir or one of
+// its parents, or "" if there is none.
+func FindProjectFile(dir string) string {
+	for {
+		for _, name := range ProjectFileNames {
+			path := filepath.Join(dir, name)
+			if info, err := os.Stat(path); err == nil && !info.IsDir() {
+				return path
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// LoadProject reads a project config file.
+func LoadProject(path string) (*Project, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := decodeRaw(path, detectFormat(path, data), data)
+	if err != nil {
+		return nil, err
+	}
+	if v, ok := raw["version"].(float64); ok && int(v) > CurrentVersion {
+		return nil, fmt.Errorf("%s has config version %d, but this Grove only supports up to %d; upgrade Grove", path, int(v), CurrentVersion)
+	}
+	if _, ok := raw["features"]; ok {
+		return nil, fmt.Errorf("%s: features can only be kept in the global config", path)
+	}
+
+	var p Project
+	if err := decodeStrict(path, raw, &p); err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Dir(path)
+	resolve := func(p string) string {
+		if p == "" || filepath.IsAbs(p) || strings.HasPrefix(p, "~") {
+			return p
+		}
+		return filepath.Join(dir, p)
+	}
+	p.RootDir = resolve(p.RootDir)
+	for name, set := range p.Sets {
+		set.SkillsDir = resolve(set.SkillsDir)
+		p.Sets[name] = set
+	}
+	return &p, nil
+}
+
+// ApplyProject finds the project config nearest to dir and layers it over c:
+// its root_dir, watch_command and jobs replace the global ones, new sets are
+// added, and for sets that exist globally its repos and skills_dir replace the
+// global ones while its tasks are merged in by name. It returns the project
+// file used, or "" if there is none.
+//
+// Saving c still writes only the global config. Values that come from the
+// project, including every part of a set only the project defines, cannot be
+// changed through c: saving such a change fails, and the project file has to
+// be edited instead.
+func (c *Config) ApplyProject(dir string) (string, error) {
+	path := FindProjectFile(dir)
+	if path == "" {
+		return "", nil
+	}
+	p, err := LoadProject(path)
+	if err != nil {
+		return "", err
+	}
+	c.applyOverlay(path, p)
+	return path, nil
+}
+
+// ProjectFile returns the project config layered over c, or "".
+func (c *Config) ProjectFile() string {
+	if c.overlay == nil {
+		return ""
+	}
+	return c.overlay.path
+}
+
+func (c *Config) applyOverlay(path string, p *Project) {
+	ov := &overlay{
+		path:        path,
+		globalRoot:  c.RootDir,
+		globalWatch: c.WatchCommand,
+		globalJobs:  c.Jobs,
+		sets:        make(map[string]*setOverlay),
+	}
+	c.project = p
+
+	if p.RootDir != "" {
+		c.RootDir, ov.root = p.RootDir, true
+	}
+	if p.WatchCommand != "" {
+		c.WatchCommand, ov.watch = p.WatchCommand, true
+	}
+	if p.Jobs != 0 {
+		c.Jobs, ov.jobs = p.Jobs, true
+	}
+
+	for name, ps := range p.Sets {
+		so := &setOverlay{}
+		set, exists := c.Sets[name]
+		if exists {
+			global := cloneSet(set)
+			so.global = &global
+		}
+
+		if ps.Repos != nil {
+			set.Repos, so.repos = ps.Repos, true
+		}
+		if ps.SkillsDir != "" {
+			set.SkillsDir, so.skillsDir = ps.SkillsDir, true
+		}
+		if len(ps.Tasks) > 0 {
+			tasks := make(map[string]Task, len(set.Tasks)+len(ps.Tasks))
+			for k, t := range set.Tasks {
+				tasks[k] = t
+			}
+			for k, t := range ps.Tasks {
+				tasks[k] = t
+				so.tasks = append(so.tasks, k)
+			}
+			set.Tasks = tasks
+		}
+		c.Sets[name] = set
+		so.applied = cloneSet(set)
+		ov.sets[name] = so
+	}
+	c.overlay = ov
+}
+
+// cloneSet copies a set deeply, so that later edits to the original through
+// its slices and maps leave the copy alone.
+func cloneSet(s Set) Set {
+	if s.Repos != nil {
+		s.Repos = append([]string{}, s.Repos...)
+	}
+	if s.Tasks != nil {
+		tasks := make(map[string]Task, len(s.Tasks))
+		for name, t := range s.Tasks {
+			if t.Repos != nil {
+				repos := make(map[string]string, len(t.Repos))
+				for k, v := range t.Repos {
+					repos[k] = v
+				}
+				t.Repos = repos
+			}
+			if t.DependsOn != nil {
+				deps := make(map[string][]string, len(t.DependsOn))
+				for k, v := range t.DependsOn {
+					deps[k] = append([]string{}, v...)
+				}
+				t.DependsOn = deps
+			}
+			tasks[name] = t
+		}
+		s.Tasks = tasks
+	}
+	return s
+}
+
+// checkOverlay fails if a value contributed by the project layer was changed
+// or removed, since saving could not keep the change.
+func (c *Config) checkOverlay() error {
+	ov := c.overlay
+	if ov == nil {
+		return nil
+	}
+	fromProject := func(what string) error {
+		return fmt.Errorf("%s comes from the project config %s; edit it there", what, ov.path)
+	}
+
+	if ov.root && c.RootDir != c.project.RootDir {
+		return fromProject("root_dir")
+	}
+	if ov.watch && c.WatchCommand != c.project.WatchCommand {
+		return fromProject("watch_command")
+	}
+	if ov.jobs && c.Jobs != c.project.Jobs {
+		return fromProject("jobs")
+	}
+
+	for name, so := range ov.sets {
+		set, ok := c.Sets[name]
+		switch {
+		case !ok || (so.global == nil && !reflect.DeepEqual(set, so.applied)):
+			return fromProject(fmt.Sprintf("set '%s'", name))
+		case so.repos && !reflect.DeepEqual(set.Repos, so.applied.Repos):
+			return fromProject(fmt.Sprintf("the repos of set '%s'", name))
+		case so.skillsDir && set.SkillsDir != so.applied.SkillsDir:
+			return fromProject(fmt.Sprintf("the skills_dir of set '%s'", name))
+		}
+		for _, task := range so.tasks {
+			if t, ok := set.Tasks[task]; !ok || !reflect.DeepEqual(t, so.applied.Tasks[task]) {
+				return fromProject(fmt.Sprintf("task '%s' of set '%s'", task, name))
+			}
+		}
+	}
+	return nil
+}
+
+// withoutOverlay returns the config to write to the global file: every value
+// the project layer contributed is replaced by the global one, or removed if
+// there was none. Other changes to the sets it touched are kept.
+func (c *Config) withoutOverlay() *Config {
+	ov := c.overlay
+	if ov == nil {
+		return c
+	}
+
+	out := *c
+	if ov.root {
+		out.RootDir = ov.globalRoot
+	}
+	if ov.watch {
+		out.WatchCommand = ov.globalWatch
+	}
+	if ov.jobs {
+		out.Jobs = ov.globalJobs
+	}
+
+	out.Sets = make(map[string]Set, len(c.Sets))
+	for name, set := range c.Sets {
+		out.Sets[name] = set
+	}
+	for name, so := range ov.sets {
+		set, ok := out.Sets[name]
+		if !ok {
+			continue
+		}
+		if so.global == nil {
+			delete(out.Sets, name)
+			continue
+		}
+
+		if so.repos {
+			set.Repos = so.global.Repos
+		}
+		if so.skillsDir {
+			set.SkillsDir = so.global.SkillsDir
+		}
+		if len(so.tasks) > 0 {
+			tasks := make(map[string]Task, len(set.Tasks))
+			for k, t := range set.Tasks {
+				tasks[k] = t
+			}
+			for _, k := range so.tasks {
+				if t, ok := so.global.Tasks[k]; ok {
+					tasks[k] = t
+				} else {
+					delete(tasks, k)
+				}
+			}
+			if len(tasks) == 0 {
+				tasks = nil
+			}
+			set.Tasks = tasks
+		}
+		out.Sets[name] = set
+	}
+	return &out
+}
@@ -1,22 +1,37 @@
 package git
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/vedantprajapati/Grove/internal/proc"
 )
 
+// Out receives the progress messages printed while cloning, creating
+// worktrees and syncing. Commands that write data to stdout point it elsewhere.
+var Out io.Writer = os.Stdout
+
 // RunGit executes a git command in the specified directory.
 func RunGit(cwd string, args ...string) (string, error) {
+	return RunGitContext(context.Background(), cwd, args...)
+}
+
+// RunGitContext is RunGit with a context. Git runs in its own process group,
+// which is sent the proc.Interrupted signal, or killed, if ctx is cancelled.
+func RunGitContext(ctx context.Context, cwd string, args ...string) (string, error) {
 	var output []byte
 	var err error
 
 	// Retry up to 3 times for lock errors or transient fs issues
 	for i := 0; i < 3; i++ {
-		cmd := exec.Command("git", args...)
+		cmd := proc.CommandContext(ctx, "git", args...)
 		if cwd != "" {
 			cmd.Dir = cwd
 		}
@@ -38,9 +53,44 @@ func RunGit(cwd string, args ...string) (string, error) {
 		break
 	}
 
+	if ctx.Err() != nil {
+		return "", fmt.Errorf("git command cancelled: %s: %v", strings.Join(args, " "), ctx.Err())
+	}
 	return "", fmt.Errorf("git command failed: %s\nOutput: %s", strings.Join(args, " "), string(output))
 }
 
+// RunGitRaw runs a git command and returns its stdout untouched, for output
+// such as patches where whitespace matters. Stderr is included in the error.
+func RunGitRaw(cwd string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = cwd
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git command failed: %s\nOutput: %s", strings.Join(args, " "), stderr.String())
+	}
+	return string(out), nil
+}
+
+// Grep runs `git grep` with args and returns its stdout. Unlike other git
+// commands, exit status 1 only means nothing matched and is not an error.
+func Grep(cwd string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"grep"}, args...)...)
+	cmd.Dir = cwd
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 && stderr.Len() == 0 {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("git grep failed: %s", strings.TrimSpace(stderr.String()))
+	}
+	return string(out), nil
+}
+
 // RunCommand executes an arbitrary command in the specified directory.
 func RunCommand(cwd string, name string, args ...string) (string, error) {
 	cmd := exec.Command(name, args...)
@@ -83,7 +133,7 @@ func EnsureBareRepo(url, cacheDir string) (string, error) {
 			return "", err
 		}
 
-		fmt.Printf("Cloning %s to %s...\n", url, barePath)
+		fmt.Fprintf(Out, "Cloning %s to %s...\n", url, barePath)
 		if _, err := RunGit("", "clone", "--bare", url, barePath); err != nil {
 			return "", err
 		}
@@ -106,7 +156,7 @@ func CreateWorktree(barePath, branchName, targetPath string) error {
 	barePath = filepath.ToSlash(barePath)
 
 	// Use -B to force create/reset branch. This handles both new and existing branches.
-	fmt.Printf("  Creating worktree at %s (branch %s)...\n", targetPath, branchName)
+	fmt.Fprintf(Out, "  Creating worktree at %s (branch %s)...\n", targetPath, branchName)
 	_, err := RunGit(barePath, "worktree", "add", "-B", branchName, targetPath)
 
 	if err != nil {
@@ -144,31 +194,55 @@ func RemoveWorktree(barePath, worktreePath string) error {
 // Note: This is complex in a worktree.
 // Ideally: git fetch origin, then git merge/rebase.
 func SyncRepo(worktreePath string) error {
+	return SyncRepoContext(context.Background(), worktreePath)
+}
+
+// SyncRepoContext is SyncRepo with a context; see RunGitContext for how
+// cancelling it stops git.
+func SyncRepoContext(ctx context.Context, worktreePath string) error {
 	// Verify it is a git repo
 	if _, err := os.Stat(filepath.Join(worktreePath, ".git")); os.IsNotExist(err) {
 		// Worktrees have a .git file, not a directory. os.Stat handles both.
 	}
 
-	fmt.Printf("Syncing %s...\n", worktreePath)
-	if _, err := RunGit(worktreePath, "fetch", "--all"); err != nil {
+	fmt.Fprintf(Out, "Syncing %s...\n", worktreePath)
+	if _, err := RunGitContext(ctx, worktreePath, "fetch", "--all"); err != nil {
 		return fmt.Errorf("fetch failed: %v", err)
 	}
 
 	// Check if upstream exists
-	_, err := RunGit(worktreePath, "rev-parse", "--abbrev-ref", "@{u}")
+	_, err := RunGitContext(ctx, worktreePath, "rev-parse", "--abbrev-ref", "@{u}")
 	if err != nil {
 		// No upstream, just fetch is fine
-		fmt.Printf("  No upstream for %s, skipped pull.\n", worktreePath)
+		fmt.Fprintf(Out, "  No upstream for %s, skipped pull.\n", worktreePath)
 		return nil
 	}
 
 	// Attempt pull (which is fetch + merge)
-	if _, err := RunGit(worktreePath, "pull"); err != nil {
+	if _, err := RunGitContext(ctx, worktreePath, "pull"); err != nil {
 		return fmt.Errorf("pull failed in %s: %v", worktreePath, err)
 	}
 	return nil
 }
 
+// PushRepo pushes the current branch to origin and sets it as the upstream.
+func PushRepo(worktreePath string) error {
+	return PushRepoContext(context.Background(), worktreePath)
+}
+
+// PushRepoContext is PushRepo with a context; see RunGitContext for how
+// cancelling it stops git.
+func PushRepoContext(ctx context.Context, worktreePath string) error {
+	branch, err := BranchName(worktreePath)
+	if err != nil {
+		return err
+	}
+	if _, err := RunGitContext(ctx, worktreePath, "push", "-u", "origin", branch); err != nil {
+		return fmt.Errorf("push failed in %s: %v", worktreePath, err)
+	}
+	return nil
+}
+
 // GetStatus returns the status of a repository (dirty/clean and ahead/behind).
 func GetStatus(repoPath string) (bool, string, error) {
 	// Check if dirty
@@ -189,6 +263,39 @@ func GetStatus(repoPath string) (bool, string, error) {
 	return isDirty, ab, nil
 }
 
+// FetchBare updates a bare cache from its origin. Remote branches are stored
+// under refs/remotes/origin so branches checked out in worktrees are never touched.
+func FetchBare(barePath string) error {
+	if _, err := RunGit(barePath, "config", "remote.origin.fetch", "+refs/heads/*:refs/remotes/origin/*"); err != nil {
+		return err
+	}
+	if _, err := RunGit(barePath, "fetch", "--prune", "origin"); err != nil {
+		return fmt.Errorf("fetch failed in %s: %v", barePath, err)
+	}
+	return nil
+}
+
+// DefaultBranch returns the branch HEAD points at.
+// For a bare cache this is the remote's default branch (e.g. main).
+func DefaultBranch(repoPath string) (string, error) {
+	return RunGit(repoPath, "symbolic-ref", "--short", "HEAD")
+}
+
+// RevParse resolves a revision to a commit SHA.
+func RevParse(repoPath, rev string) (string, error) {
+	return RunGit(repoPath, "rev-parse", "--verify", "--quiet", rev+"^{commit}")
+}
+
+// ResolveBase returns the best ref to compare against for a base branch.
+// The fetched origin/<base> is preferred over the local branch, which is only
+// updated when the cache was first cloned.
+func ResolveBase(repoPath, base string) string {
+	if _, err := RevParse(repoPath, "origin/"+base); err == nil {
+		return "origin/" + base
+	}
+	return base
+}
+
 // BranchName returns the current branch name.
 func BranchName(repoPath string) (string, error) {
 	return RunGit(repoPath, "rev-parse", "--abbrev-ref", "HEAD")
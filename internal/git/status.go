@@ -0,0 +1,224 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WorktreeStatus is the state of a worktree as reported by
+// `git status --porcelain=v2 --branch --show-stash`.
+type WorktreeStatus struct {
+	Branch      string // "HEAD" when detached
+	Detached    bool
+	Head        string // Commit SHA; empty on an unborn branch
+	Upstream    string
+	HasUpstream bool // Upstream is set and exists, so Ahead/Behind are known
+	Ahead       int
+	Behind      int
+	Staged      int
+	Unstaged    int
+	Untracked   int
+	Conflicted  int
+	Stashes     int
+	Operation   string // In-progress operation: rebase, am, merge, cherry-pick, revert, bisect or ""
+}
+
+// Dirty reports whether the worktree has any uncommitted changes.
+func (s *WorktreeStatus) Dirty() bool {
+	return s.Staged+s.Unstaged+s.Untracked+s.Conflicted > 0
+}
+
+// CommitInfo describes a single commit.
+type CommitInfo struct {
+	SHA     string    `json:"sha"`
+	Subject string    `json:"subject"`
+	Author  string    `json:"author"`
+	Time    time.Time `json:"time"`
+}
+
+// Status reads the branch, upstream, change counts and stash count of a
+// worktree with a single git call, and checks the git directory for an
+// in-progress operation. Git before 2.35 rejects --show-stash in porcelain v2;
+// there the stashes are counted with `git stash list` instead.
+func Status(repoPath string) (*WorktreeStatus, error) {
+	out, err := RunGit(repoPath, "status", "--porcelain=v2", "--branch", "--show-stash")
+	if err == nil {
+		status := parsePorcelainV2(out)
+		status.Operation = inProgressOperation(repoPath)
+		return status, nil
+	}
+
+	out, retryErr := RunGit(repoPath, "status", "--porcelain=v2", "--branch")
+	if retryErr != nil {
+		return nil, err
+	}
+	status := parsePorcelainV2(out)
+	status.Stashes = countStashes(repoPath)
+	status.Operation = inProgressOperation(repoPath)
+	return status, nil
+}
+
+// countStashes returns the number of stash entries, or 0 if they cannot be listed.
+func countStashes(repoPath string) int {
+	out, err := RunGit(repoPath, "stash", "list")
+	if err != nil || out == "" {
+		return 0
+	}
+	return len(strings.Split(out, "\n"))
+}
+
+// parsePorcelainV2 parses the output of `git status --porcelain=v2 --branch --show-stash`.
+func parsePorcelainV2(out string) *WorktreeStatus {
+	status := &WorktreeStatus{}
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		switch fields[0] {
+		case "#":
+			parseHeader(status, fields[1:])
+		case "1", "2":
+			// "1 XY ..." where X is the index state and Y the worktree state.
+			if xy := fields[1]; len(xy) == 2 {
+				if xy[0] != '.' {
+					status.Staged++
+				}
+				if xy[1] != '.' {
+					status.Unstaged++
+				}
+			}
+		case "u":
+			status.Conflicted++
+		case "?":
+			status.Untracked++
+		}
+	}
+	return status
+}
+
+func parseHeader(status *WorktreeStatus, fields []string) {
+	if len(fields) < 2 {
+		return
+	}
+	switch fields[0] {
+	case "branch.oid":
+		if fields[1] != "(initial)" {
+			status.Head = fields[1]
+		}
+	case "branch.head":
+		status.Branch = fields[1]
+		if fields[1] == "(detached)" {
+			status.Branch = "HEAD"
+			status.Detached = true
+		}
+	case "branch.upstream":
+		status.Upstream = fields[1]
+	case "branch.ab":
+		if len(fields) == 3 {
+			status.HasUpstream = true
+			status.Ahead, _ = strconv.Atoi(strings.TrimPrefix(fields[1], "+"))
+			status.Behind, _ = strconv.Atoi(strings.TrimPrefix(fields[2], "-"))
+		}
+	case "stash":
+		status.Stashes, _ = strconv.Atoi(fields[1])
+	}
+}
+
+// inProgressOperation looks for the state files git leaves in the git
+// directory while an operation is stopped part way through.
+func inProgressOperation(repoPath string) string {
+	gitDir, err := gitDirOf(repoPath)
+	if err != nil {
+		return ""
+	}
+	exists := func(name string) bool {
+		_, err := os.Stat(filepath.Join(gitDir, name))
+		return err == nil
+	}
+
+	switch {
+	case exists("rebase-merge"):
+		return "rebase"
+	case exists("rebase-apply"):
+		if exists(filepath.Join("rebase-apply", "applying")) {
+			return "am"
+		}
+		return "rebase"
+	case exists("MERGE_HEAD"):
+		return "merge"
+	case exists("CHERRY_PICK_HEAD"):
+		return "cherry-pick"
+	case exists("REVERT_HEAD"):
+		return "revert"
+	case exists("BISECT_LOG"):
+		return "bisect"
+	}
+	return ""
+}
+
+// gitDirOf returns the git directory of a repo or worktree without running
+// git. In a linked worktree, .git is a file pointing at the real directory.
+func gitDirOf(repoPath string) (string, error) {
+	dotGit := filepath.Join(repoPath, ".git")
+	info, err := os.Stat(dotGit)
+	if err != nil {
+		return "", err
+	}
+	if info.IsDir() {
+		return dotGit, nil
+	}
+
+	data, err := os.ReadFile(dotGit)
+	if err != nil {
+		return "", err
+	}
+	dir := strings.TrimSpace(strings.TrimPrefix(string(data), "gitdir:"))
+	if dir == "" {
+		return "", fmt.Errorf("invalid .git file in %s", repoPath)
+	}
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(repoPath, dir)
+	}
+	return dir, nil
+}
+
+// AheadBehind counts the commits reachable from HEAD but not from rev
+// (ahead), and from rev but not from HEAD (behind).
+func AheadBehind(repoPath, rev string) (int, int, error) {
+	out, err := RunGit(repoPath, "rev-list", "--left-right", "--count", "HEAD..."+rev)
+	if err != nil {
+		return 0, 0, err
+	}
+	var ahead, behind int
+	if _, err := fmt.Sscanf(out, "%d\t%d", &ahead, &behind); err != nil {
+		return 0, 0, fmt.Errorf("unexpected rev-list output in %s: %q", repoPath, out)
+	}
+	return ahead, behind, nil
+}
+
+// LastCommit returns the commit at HEAD.
+func LastCommit(repoPath string) (*CommitInfo, error) {
+	out, err := RunGit(repoPath, "log", "-1", "--format=%H%x00%s%x00%an%x00%ct")
+	if err != nil {
+		return nil, err
+	}
+	parts := strings.SplitN(out, "\x00", 4)
+	if len(parts) != 4 {
+		return nil, fmt.Errorf("unexpected git log output in %s: %q", repoPath, out)
+	}
+	unix, err := strconv.ParseInt(parts[3], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("unexpected commit time in %s: %q", repoPath, parts[3])
+	}
+	return &CommitInfo{
+		SHA:     parts[0],
+		Subject: parts[1],
+		Author:  parts[2],
+		Time:    time.Unix(unix, 0),
+	}, nil
+}
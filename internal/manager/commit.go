@@ -0,0 +1,142 @@
+package manager
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/vedantprajapati/Grove/internal/git"
+)
+
+// FeatureTrailer is the commit trailer that links the sibling commits made by
+// CommitFeature across the repos of a feature.
+const FeatureTrailer = "Grove-Feature"
+
+// CommitOptions controls what CommitFeature stages and commits.
+type CommitOptions struct {
+	Message   string
+	All       bool     // Stage modified and deleted tracked files, like git commit -a
+	Pathspecs []string // Stage these paths, including untracked files
+	Trailer   bool     // Add a "Grove-Feature: <feature>" trailer
+
+	// Confirm, when set, is shown each repo's staged diff before it is
+	// committed; the repo is skipped unless it returns true, and its index is
+	// put back as it was. Repos are then handled one at a time in set order.
+	Confirm func(repo, diff string) bool
+}
+
+// CommitResult is the outcome of committing in one repo. SHA is empty when
+// the repo was skipped or failed.
+type CommitResult struct {
+	Repo    string `json:"repo"`
+	SHA     string `json:"sha,omitempty"`
+	Skipped string `json:"skipped,omitempty"` // Why nothing was committed: clean, nothing staged or declined
+	Error   string `json:"error,omitempty"`
+}
+
+// CommitFeature stages and commits with the same message in every dirty repo
+// of a feature. Clean repos and repos with nothing staged are skipped.
+func (m *Manager) CommitFeature(featureName string, opts CommitOptions) ([]CommitResult, error) {
+	if strings.TrimSpace(opts.Message) == "" {
+		return nil, fmt.Errorf("a commit message is required")
+	}
+	feat, repos, err := m.featureRepos(featureName)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := m.jobs()
+	if opts.Confirm != nil {
+		jobs = 1
+	}
+
+	results := make([]CommitResult, len(repos))
+	forEachRepo(repos, jobs, func(i int, url string) {
+		repoName := git.GetRepoNameFromURL(url)
+		results[i] = CommitResult{Repo: repoName}
+		if err := commitRepo(featureName, filepath.Join(feat.Path, repoName), repoName, opts, &results[i]); err != nil {
+			results[i].Error = err.Error()
+		}
+	})
+
+	var errors []string
+	for _, r := range results {
+		if r.Error != "" {
+			errors = append(errors, fmt.Sprintf("error committing %s: %s", r.Repo, r.Error))
+		}
+	}
+	if len(errors) > 0 {
+		return results, fmt.Errorf("commit failed for some repositories:\n%s", strings.Join(errors, "\n"))
+	}
+	return results, nil
+}
+
+func commitRepo(featureName, repoPath, repoName string, opts CommitOptions, res *CommitResult) (err error) {
+	status, err := git.Status(repoPath)
+	if err != nil {
+		return err
+	}
+	if !status.Dirty() {
+		res.Skipped = "clean"
+		return nil
+	}
+
+	// Remember the index so a repo that ends up not committed is left
+	// staged exactly as it was found.
+	committed := false
+	if opts.All || len(opts.Pathspecs) > 0 {
+		index, err := git.RunGit(repoPath, "write-tree")
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if committed {
+				return
+			}
+			if _, restoreErr := git.RunGit(repoPath, "read-tree", index); restoreErr != nil && err == nil {
+				err = fmt.Errorf("failed to restore the index: %v", restoreErr)
+			}
+		}()
+	}
+
+	if opts.All {
+		if _, err := git.RunGit(repoPath, "add", "--update"); err != nil {
+			return err
+		}
+	}
+	// Pathspecs are added one at a time: git adds nothing when any of them
+	// matches no file, and the same paths rarely exist in every repo.
+	for _, spec := range opts.Pathspecs {
+		if _, err := git.RunGit(repoPath, "add", "--all", "--", spec); err != nil && !strings.Contains(err.Error(), "did not match any files") {
+			return err
+		}
+	}
+
+	if _, err := git.RunGit(repoPath, "diff", "--cached", "--quiet"); err == nil {
+		res.Skipped = "nothing staged"
+		return nil
+	}
+
+	if opts.Confirm != nil {
+		diff, err := git.RunGitRaw(repoPath, "diff", "--cached", "--stat", "--patch",
+			"--src-prefix=a/"+repoName+"/", "--dst-prefix=b/"+repoName+"/")
+		if err != nil {
+			return err
+		}
+		if !opts.Confirm(repoName, diff) {
+			res.Skipped = "declined"
+			return nil
+		}
+	}
+
+	args := []string{"commit", "--quiet", "-m", opts.Message}
+	if opts.Trailer {
+		args = append(args, "--trailer", FeatureTrailer+": "+featureName)
+	}
+	if _, err := git.RunGit(repoPath, args...); err != nil {
+		return err
+	}
+	committed = true
+	res.SHA, err = git.RevParse(repoPath, "HEAD")
+	return err
+}
@@ -0,0 +1,116 @@
+package manager
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Orders accepted by SortSummaries.
+const (
+	SortByName     = "name"     // Set, then feature name
+	SortByActivity = "activity" // Most recent activity first
+	SortByStale    = "stale"    // Least recent activity first
+	SortByDirty    = "dirty"    // Most dirty repos first
+)
+
+// FeatureSummary rolls up the status of every repo in a feature into one row
+// of the `gr status` dashboard. The counts are numbers of repos.
+type FeatureSummary struct {
+	Feature      string    `json:"feature"`
+	Set          string    `json:"set"`
+	Path         string    `json:"path"`
+	Repos        int       `json:"repos"`
+	Dirty        int       `json:"dirty"`
+	Ahead        int       `json:"ahead"`
+	Behind       int       `json:"behind"`
+	Changed      int       `json:"changed"`     // Repos with commits beyond their base
+	Conflicted   int       `json:"conflicted"`  // Repos with conflicted files
+	InProgress   int       `json:"in_progress"` // Repos with a rebase, merge or similar stopped part way
+	Missing      int       `json:"missing"`
+	LastActivity time.Time `json:"last_activity"` // Newest last commit across the repos
+	Error        string    `json:"error,omitempty"`
+}
+
+// SummarizeFeature rolls up the repo statuses of a feature.
+func (m *Manager) SummarizeFeature(featureName string, statuses []RepoStatus) FeatureSummary {
+	feat := m.Config.Features[featureName]
+	sum := FeatureSummary{
+		Feature: featureName,
+		Set:     feat.Set,
+		Path:    feat.Path,
+		Repos:   len(statuses),
+	}
+	for _, s := range statuses {
+		if s.IsDirty {
+			sum.Dirty++
+		}
+		if s.Ahead > 0 {
+			sum.Ahead++
+		}
+		if s.Behind > 0 {
+			sum.Behind++
+		}
+		if s.Changed {
+			sum.Changed++
+		}
+		if s.Conflicted > 0 {
+			sum.Conflicted++
+		}
+		if s.Operation != "" {
+			sum.InProgress++
+		}
+		if s.Missing {
+			sum.Missing++
+		}
+		if s.LastCommit != nil && s.LastCommit.Time.After(sum.LastActivity) {
+			sum.LastActivity = s.LastCommit.Time
+		}
+	}
+	return sum
+}
+
+// SortSummaries orders summaries by one of the SortBy* orders, keeping
+// features of the same set together.
+func SortSummaries(sums []FeatureSummary, order string) error {
+	var less func(a, b FeatureSummary) bool
+	switch order {
+	case "", SortByName:
+		less = func(a, b FeatureSummary) bool { return a.Feature < b.Feature }
+	case SortByActivity:
+		less = func(a, b FeatureSummary) bool { return a.LastActivity.After(b.LastActivity) }
+	case SortByStale:
+		less = func(a, b FeatureSummary) bool { return a.LastActivity.Before(b.LastActivity) }
+	case SortByDirty:
+		less = func(a, b FeatureSummary) bool { return a.Dirty > b.Dirty }
+	default:
+		return fmt.Errorf("invalid sort '%s' (want name, activity, stale or dirty)", order)
+	}
+
+	sort.SliceStable(sums, func(i, j int) bool {
+		if sums[i].Set != sums[j].Set {
+			return sums[i].Set < sums[j].Set
+		}
+		if less(sums[i], sums[j]) {
+			return true
+		}
+		if less(sums[j], sums[i]) {
+			return false
+		}
+		return sums[i].Feature < sums[j].Feature
+	})
+	return nil
+}
+
+// StaleSummaries returns the summaries with no activity in the last d.
+// Features without any commits count as stale.
+func StaleSummaries(sums []FeatureSummary, d time.Duration) []FeatureSummary {
+	cutoff := time.Now().Add(-d)
+	var stale []FeatureSummary
+	for _, s := range sums {
+		if s.LastActivity.Before(cutoff) {
+			stale = append(stale, s)
+		}
+	}
+	return stale
+}
@@ -0,0 +1,116 @@
+package manager
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/vedantprajapati/Grove/internal/git"
+)
+
+// DiffOptions selects what DiffFeature compares.
+type DiffOptions struct {
+	Base      bool     // Compare with the merge-base of the base branch instead of HEAD
+	Color     bool     // Ask git for colored patches
+	Pathspecs []string // Limit the diff to these paths in each repo
+}
+
+// RepoDiff is the diff of a single repo, with paths prefixed by the repo name
+// so the patches of a feature can be concatenated and applied from its root.
+type RepoDiff struct {
+	Repo  string     `json:"repo"`
+	From  string     `json:"from"` // Commit the diff starts from
+	Files []DiffFile `json:"files"`
+	Patch string     `json:"-"`
+	Error string     `json:"error,omitempty"`
+}
+
+// DiffFile is one changed file in a RepoDiff.
+type DiffFile struct {
+	Path    string `json:"path"` // Prefixed with the repo name
+	Added   int    `json:"added"`
+	Deleted int    `json:"deleted"`
+	Binary  bool   `json:"binary,omitempty"`
+}
+
+// DiffFeature diffs every selected repo of a feature, in set order. By
+// default it shows uncommitted changes; with opts.Base it shows everything
+// the feature changed since it left its base branch. Untracked files are not
+// included, as with git diff.
+func (m *Manager) DiffFeature(featureName string, opts DiffOptions) ([]RepoDiff, error) {
+	feat, repos, err := m.featureRepos(featureName)
+	if err != nil {
+		return nil, err
+	}
+
+	diffs := make([]RepoDiff, len(repos))
+	forEachRepo(repos, m.jobs(), func(i int, url string) {
+		repoName := git.GetRepoNameFromURL(url)
+		diffs[i] = RepoDiff{Repo: repoName}
+		if err := m.diffRepo(feat.Path, repoName, m.baseBranch(feat, repoName), opts, &diffs[i]); err != nil {
+			diffs[i].Error = err.Error()
+		}
+	})
+	return diffs, nil
+}
+
+func (m *Manager) diffRepo(featurePath, repoName, base string, opts DiffOptions, d *RepoDiff) error {
+	repoPath := filepath.Join(featurePath, repoName)
+
+	from := "HEAD"
+	if opts.Base {
+		if base == "" {
+			return fmt.Errorf("no base branch known for %s", repoName)
+		}
+		mb, err := git.RunGit(repoPath, "merge-base", "HEAD", git.ResolveBase(repoPath, base))
+		if err != nil {
+			return fmt.Errorf("no merge-base with %s: %v", base, err)
+		}
+		from = mb
+	}
+	d.From, _ = git.RevParse(repoPath, from)
+
+	pathspecs := append([]string{"--"}, opts.Pathspecs...)
+
+	// Renames are shown as a delete and an add: git writes the rename lines
+	// of a patch without the repo prefix, so the combined patch would not
+	// apply from the feature directory.
+	numstat, err := git.RunGitRaw(repoPath, append([]string{"diff", "--no-renames", "--numstat", "-z", from}, pathspecs...)...)
+	if err != nil {
+		return err
+	}
+	d.Files = parseNumstat(numstat, repoName)
+
+	args := []string{"diff", "--no-renames", "--src-prefix=a/" + repoName + "/", "--dst-prefix=b/" + repoName + "/"}
+	if opts.Color {
+		args = append(args, "--color=always")
+	} else {
+		args = append(args, "--no-color")
+	}
+	args = append(append(args, from), pathspecs...)
+	d.Patch, err = git.RunGitRaw(repoPath, args...)
+	return err
+}
+
+// parseNumstat parses `git diff --numstat -z` output: entries of
+// "<added>\t<deleted>\t<path>" separated by NULs, with "-" counts for binary
+// files.
+func parseNumstat(out, repoName string) []DiffFile {
+	files := []DiffFile{}
+	for _, entry := range strings.Split(out, "\x00") {
+		parts := strings.SplitN(entry, "\t", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		f := DiffFile{Path: repoName + "/" + parts[2]}
+		if parts[0] == "-" {
+			f.Binary = true
+		} else {
+			f.Added, _ = strconv.Atoi(parts[0])
+			f.Deleted, _ = strconv.Atoi(parts[1])
+		}
+		files = append(files, f)
+	}
+	return files
+}
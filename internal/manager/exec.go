@@ -0,0 +1,509 @@
+package manager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/vedantprajapati/Grove/internal/config"
+	"github.com/vedantprajapati/Grove/internal/git"
+	"github.com/vedantprajapati/Grove/internal/proc"
+)
+
+// prefixColors are cycled through to tell repos apart in streamed output.
+var prefixColors = []string{"#58a6ff", "#ff7b72", "#2ea043", "#d2a8ff", "#e3b341", "#79c0ff", "#ffa657", "#56d364"}
+
+// Exit policies decide when an exec run as a whole counts as failed.
+const (
+	ExitPolicyAny   = "any"   // Fail if any repo failed (default)
+	ExitPolicyAll   = "all"   // Fail only if every repo failed
+	ExitPolicyNever = "never" // Always succeed; failures only show in the summary
+)
+
+// Interrupted is the cancellation cause used when the user sends a signal.
+// Cancelling an exec or sync context with it forwards the signal to every
+// child process group instead of killing them outright.
+type Interrupted = proc.Interrupted
+
+type ExecOptions struct {
+	Timeout    time.Duration // Kill a repo's command after this long; 0 means no limit
+	Shell      bool          // Run the command line through the user's shell
+	NoTemplate bool          // Pass the command line as given, without template expansion
+	Group      bool          // Print each repo's output as one block when it finishes
+	FailFast   bool          // Cancel the remaining repos after the first failure
+	ExitPolicy string        // One of the ExitPolicy* constants; defaults to ExitPolicyAny
+	Record     bool          // Save output and results under DataDir/runs
+	Stdout     io.Writer     // Defaults to os.Stdout
+	Stderr     io.Writer     // Defaults to os.Stderr
+
+	recorder *runRecorder // Shared by the features of one recorded run
+}
+
+// ExecResult is the outcome of running a command in one repo.
+type ExecResult struct {
+	Feature   string        `json:"feature"`
+	Repo      string        `json:"repo"`
+	ExitCode  int           `json:"exit_code"` // -1 if the command was cancelled
+	Duration  time.Duration `json:"duration"`
+	TimedOut  bool          `json:"timed_out"` // Killed after exceeding ExecOptions.Timeout
+	Cancelled bool          `json:"cancelled"` // Stopped by --fail-fast or an interrupt
+	Skipped   bool          `json:"skipped"`   // Not run because a task dependency failed
+	Err       error         `json:"-"`         // Non-nil if the command failed to start or exited non-zero
+}
+
+// MarshalJSON adds Err as an "error" string, which encoding/json cannot do
+// for an error value.
+func (r ExecResult) MarshalJSON() ([]byte, error) {
+	type plain ExecResult
+	out := struct {
+		plain
+		Error string `json:"error,omitempty"`
+	}{plain: plain(r)}
+	if r.Err != nil {
+		out.Error = r.Err.Error()
+	}
+	return json.Marshal(out)
+}
+
+// ExecError is returned when an exec run fails under its exit policy.
+type ExecError struct {
+	Failed []ExecResult
+	Code   int // Suggested process exit code
+}
+
+func (e *ExecError) Error() string {
+	var names []string
+	for _, r := range e.Failed {
+		names = append(names, r.Repo)
+	}
+	return fmt.Sprintf("command failed in %d repos: %s", len(e.Failed), strings.Join(names, ", "))
+}
+
+func (m *Manager) ExecFeature(featureName string, command string, args []string) error {
+	_, err := m.ExecFeatureWithOptions(context.Background(), featureName, command, args, ExecOptions{})
+	return err
+}
+
+// ExecFeatureWithOptions runs a command in every repo of a feature, at most
+// Manager.Jobs at a time. Unless opts.NoTemplate is set, the command and its
+// arguments are expanded as Go templates against RepoVars for each repo,
+// which are also exported to the child as GROVE_* environment variables.
+//
+// Output is streamed line by line with a colored [repo] prefix, or collected
+// per repo when opts.Group is set. Results are returned in set order; the
+// error is an *ExecError when the run fails under opts.ExitPolicy.
+//
+// Cancelling ctx stops every repo; see Interrupted for forwarding signals.
+func (m *Manager) ExecFeatureWithOptions(ctx context.Context, featureName string, command string, args []string, opts ExecOptions) ([]ExecResult, error) {
+	feat, ok := m.Config.Features[featureName]
+	if !ok {
+		return nil, fmt.Errorf("feature '%s' not found", featureName)
+	}
+
+	set, ok := m.Config.Sets[feat.Set]
+	if !ok {
+		return nil, fmt.Errorf("set '%s' not found for feature", feat.Set)
+	}
+
+	if err := opts.setDefaults(); err != nil {
+		return nil, err
+	}
+
+	repos, err := m.selectRepos(feat, set.Repos)
+	if err != nil {
+		return nil, err
+	}
+	if len(repos) == 0 {
+		fmt.Fprintf(opts.Stdout, "No repos in feature '%s' match the filter (%s).\n", featureName, m.Filter)
+		return nil, nil
+	}
+
+	templates, err := parseArgTemplates(append([]string{command}, args...), opts.NoTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	ownRecorder := false
+	if opts.Record && opts.recorder == nil {
+		opts.recorder = m.startRecorder(append([]string{command}, args...), opts)
+		ownRecorder = opts.recorder != nil
+	}
+
+	fmt.Fprintf(opts.Stdout, "Executing '%s %s' across %d repos (%d jobs)...\n", command, strings.Join(args, " "), len(repos), m.jobs())
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	run := newExecRun(featureName, feat, repos, opts, cancel)
+	run.ordered = newOrderedFlush()
+	results := make([]ExecResult, len(repos))
+	forEachRepo(repos, m.jobs(), func(i int, url string) {
+		results[i] = m.runRepo(ctx, run, i, git.GetRepoNameFromURL(url), templates, opts.Shell)
+	})
+
+	if ownRecorder {
+		finishRun(opts.recorder, results, opts.Stdout)
+	}
+
+	return results, execError(results, opts.ExitPolicy)
+}
+
+// ExecFeatures runs a command across several features one after another, each
+// with the same worker pool and filter as a single feature. The exit policy
+// applies to the combined results; with FailFast, later features are skipped
+// once one fails.
+func (m *Manager) ExecFeatures(ctx context.Context, featureNames []string, command string, args []string, opts ExecOptions) ([]ExecResult, error) {
+	if err := opts.setDefaults(); err != nil {
+		return nil, err
+	}
+	policy := opts.ExitPolicy
+	opts.ExitPolicy = ExitPolicyNever
+	if opts.Record {
+		// Every feature records into this one run, or none if it failed.
+		opts.recorder = m.startRecorder(append([]string{command}, args...), opts)
+		opts.Record = false
+	}
+
+	var all []ExecResult
+	for _, name := range featureNames {
+		fmt.Fprintf(opts.Stdout, "\n=== %s ===\n", name)
+		results, err := m.ExecFeatureWithOptions(ctx, name, command, args, opts)
+		if err != nil {
+			return all, err
+		}
+		all = append(all, results...)
+		if ctx.Err() != nil || (opts.FailFast && execError(results, ExitPolicyAny) != nil) {
+			break
+		}
+	}
+
+	if opts.recorder != nil {
+		finishRun(opts.recorder, all, opts.Stdout)
+	}
+	return all, execError(all, policy)
+}
+
+func (opts *ExecOptions) setDefaults() error {
+	switch opts.ExitPolicy {
+	case "":
+		opts.ExitPolicy = ExitPolicyAny
+	case ExitPolicyAny, ExitPolicyAll, ExitPolicyNever:
+	default:
+		return fmt.Errorf("unknown exit policy '%s' (use any, all or never)", opts.ExitPolicy)
+	}
+	if opts.Stdout == nil {
+		opts.Stdout = os.Stdout
+	}
+	if opts.Stderr == nil {
+		opts.Stderr = os.Stderr
+	}
+	return nil
+}
+
+// execRun holds what every repo of one exec or task run shares.
+type execRun struct {
+	featureName string
+	feat        config.Feature
+	opts        ExecOptions
+	width       int                // Longest repo name, to align prefixes
+	cancel      context.CancelFunc // Cancels the whole run for --fail-fast
+	outMu       sync.Mutex         // Keeps lines from different repos intact
+	ordered     *orderedFlush      // Flushes grouped output in set order; nil prints on completion
+}
+
+func newExecRun(featureName string, feat config.Feature, repos []string, opts ExecOptions, cancel context.CancelFunc) *execRun {
+	run := &execRun{featureName: featureName, feat: feat, opts: opts, cancel: cancel}
+	for _, url := range repos {
+		if n := len(git.GetRepoNameFromURL(url)); n > run.width {
+			run.width = n
+		}
+	}
+	return run
+}
+
+// runRepo runs a command line in the i-th repo of a run, writing its output
+// and classifying how it ended.
+func (m *Manager) runRepo(ctx context.Context, run *execRun, i int, repoName string, templates []*template.Template, shell bool) ExecResult {
+	opts := run.opts
+	prefix := repoPrefix(repoName, run.width, i)
+	vars := m.repoVars(run.featureName, run.feat, repoName)
+
+	repoCtx := ctx
+	if opts.Timeout > 0 {
+		var cancelTimeout context.CancelFunc
+		repoCtx, cancelTimeout = context.WithTimeout(ctx, opts.Timeout)
+		defer cancelTimeout()
+	}
+
+	// Recorded runs also tee both streams into the repo's log file.
+	var logW io.Writer = io.Discard
+	if opts.recorder != nil {
+		if f, err := opts.recorder.openLog(run.featureName, repoName); err != nil {
+			fmt.Fprintf(m.out(), "Warning: failed to save the output of %s: %v\n", repoName, err)
+		} else {
+			defer f.Close()
+			logW = &lockedWriter{w: f}
+		}
+	}
+
+	start := time.Now()
+	var err error
+	if opts.Group {
+		var stdout, stderr bytes.Buffer
+		err = runRepoCommand(repoCtx, vars, templates, shell, io.MultiWriter(&stdout, logW), io.MultiWriter(&stderr, logW))
+		runErr := err
+		flush := func() {
+			fmt.Fprintf(opts.Stdout, "\n--- %s ---\n", prefix)
+			opts.Stdout.Write(stdout.Bytes())
+			opts.Stderr.Write(stderr.Bytes())
+			if runErr != nil {
+				fmt.Fprintf(opts.Stderr, "Error in %s: %v\n", repoName, runErr)
+			}
+		}
+		if run.ordered != nil {
+			run.ordered.Done(i, flush)
+		} else {
+			run.outMu.Lock()
+			flush()
+			run.outMu.Unlock()
+		}
+	} else {
+		stdout := &prefixWriter{mu: &run.outMu, out: opts.Stdout, prefix: prefix}
+		stderr := &prefixWriter{mu: &run.outMu, out: opts.Stderr, prefix: prefix}
+		err = runRepoCommand(repoCtx, vars, templates, shell, io.MultiWriter(stdout, logW), io.MultiWriter(stderr, logW))
+		stdout.Flush()
+		stderr.Flush()
+		if err != nil {
+			stderr.Write([]byte(fmt.Sprintf("error: %v\n", err)))
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(logW, "error: %v\n", err)
+	}
+
+	res := ExecResult{Feature: run.featureName, Repo: repoName, Duration: time.Since(start), Err: err}
+	if err != nil {
+		res.ExitCode = exitCode(err)
+		// Only a command stopped by the cancellation counts as cancelled; one
+		// that failed on its own while a sibling's --fail-fast cancelled the
+		// run keeps its failure.
+		var stopped *cancelledError
+		switch {
+		case errors.As(err, &stopped) && stopped.cause == context.DeadlineExceeded:
+			res.TimedOut = true
+			if opts.FailFast {
+				run.cancel()
+			}
+		case stopped != nil, errors.Is(err, context.Canceled):
+			res.Cancelled = true
+		case opts.FailFast:
+			run.cancel()
+		}
+	}
+	return res
+}
+
+// RepoVars are the per-repo values available to exec templates ({{.Repo}})
+// and exported to child processes as GROVE_* environment variables.
+type RepoVars struct {
+	Repo    string
+	Branch  string
+	Feature string
+	Set     string
+	Path    string
+	Base    string
+}
+
+// Env returns the variables as GROVE_* environment entries.
+func (v RepoVars) Env() []string {
+	return []string{
+		"GROVE_REPO=" + v.Repo,
+		"GROVE_BRANCH=" + v.Branch,
+		"GROVE_FEATURE=" + v.Feature,
+		"GROVE_SET=" + v.Set,
+		"GROVE_PATH=" + v.Path,
+		"GROVE_BASE=" + v.Base,
+	}
+}
+
+func (m *Manager) repoVars(featureName string, feat config.Feature, repoName string) RepoVars {
+	repoPath := filepath.Join(feat.Path, repoName)
+	branch, _ := git.BranchName(repoPath)
+	return RepoVars{
+		Repo:    repoName,
+		Branch:  branch,
+		Feature: featureName,
+		Set:     feat.Set,
+		Path:    repoPath,
+		Base:    m.baseBranch(feat, repoName),
+	}
+}
+
+// parseArgTemplates parses every command line argument as a template up front,
+// so a typo fails the run before anything starts. With literal, every {{ is
+// escaped so the arguments expand to themselves.
+func parseArgTemplates(args []string, literal bool) ([]*template.Template, error) {
+	templates := make([]*template.Template, len(args))
+	for i, arg := range args {
+		text := arg
+		if literal {
+			text = strings.ReplaceAll(arg, "{{", `{{"{{"}}`)
+		}
+		t, err := template.New(fmt.Sprintf("arg%d", i)).Option("missingkey=error").Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid template in '%s': %v", arg, err)
+		}
+		templates[i] = t
+	}
+	return templates, nil
+}
+
+// runRepoCommand expands the command line for one repo and runs it there.
+// In shell mode the arguments are joined and passed to the user's shell.
+// The command runs in its own process group; see proc.CommandContext.
+func runRepoCommand(ctx context.Context, vars RepoVars, templates []*template.Template, shell bool, stdout, stderr io.Writer) error {
+	argv := make([]string, len(templates))
+	for i, t := range templates {
+		var buf bytes.Buffer
+		if err := t.Execute(&buf, vars); err != nil {
+			return fmt.Errorf("template error: %v", err)
+		}
+		argv[i] = buf.String()
+	}
+
+	var c *exec.Cmd
+	if shell {
+		name, flag := userShell()
+		c = proc.CommandContext(ctx, name, flag, strings.Join(argv, " "))
+	} else {
+		c = proc.CommandContext(ctx, argv[0], argv[1:]...)
+	}
+	c.Dir = vars.Path
+	c.Env = append(os.Environ(), vars.Env()...)
+	c.Stdout = stdout
+	c.Stderr = stderr
+
+	// Cancel only runs when ctx ends before the command has exited, and
+	// succeeds only if the command was still there to signal.
+	signalled := false
+	cancel := c.Cancel
+	c.Cancel = func() error {
+		err := cancel()
+		signalled = err == nil
+		return err
+	}
+	if err := c.Run(); err != nil {
+		if signalled {
+			return &cancelledError{cause: context.Cause(ctx), err: err}
+		}
+		return err
+	}
+	return nil
+}
+
+// cancelledError is the error of a command that was signalled because its
+// context ended, as opposed to one that failed on its own.
+type cancelledError struct {
+	cause error // context.Cause of the command's context
+	err   error
+}
+
+func (e *cancelledError) Error() string { return e.err.Error() }
+func (e *cancelledError) Unwrap() error { return e.err }
+
+// exitCode extracts the process exit code from a command error.
+// Commands that could not be started report 127, like a shell would,
+// and commands cancelled before or while running report -1.
+func exitCode(err error) int {
+	var stopped *cancelledError
+	if errors.As(err, &stopped) {
+		return -1
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return -1
+	}
+	return 127
+}
+
+// execError applies an exit policy to a set of results.
+func execError(results []ExecResult, policy string) error {
+	var failed []ExecResult
+	for _, r := range results {
+		if r.Err != nil {
+			failed = append(failed, r)
+		}
+	}
+	if len(failed) == 0 || policy == ExitPolicyNever {
+		return nil
+	}
+	if policy == ExitPolicyAll && len(failed) < len(results) {
+		return nil
+	}
+
+	code := 1
+	for _, r := range failed {
+		if !r.Cancelled && r.ExitCode > 0 {
+			code = r.ExitCode
+			break
+		}
+	}
+	return &ExecError{Failed: failed, Code: code}
+}
+
+// repoPrefix renders the colored, padded [repo] label for the i-th repo of a set.
+func repoPrefix(repoName string, width, i int) string {
+	label := fmt.Sprintf("[%s]%s", repoName, strings.Repeat(" ", width-len(repoName)))
+	color := prefixColors[i%len(prefixColors)]
+	return lipgloss.NewStyle().Foreground(lipgloss.Color(color)).Render(label)
+}
+
+// prefixWriter writes complete lines to out, each preceded by prefix.
+// Partial lines are held until their newline arrives or Flush is called.
+type prefixWriter struct {
+	mu     *sync.Mutex
+	out    io.Writer
+	prefix string
+	buf    []byte
+}
+
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.writeLine(w.buf[:i+1])
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// Flush writes any trailing output that did not end in a newline.
+func (w *prefixWriter) Flush() {
+	if len(w.buf) > 0 {
+		w.writeLine(append(w.buf, '\n'))
+		w.buf = nil
+	}
+}
+
+func (w *prefixWriter) writeLine(line []byte) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	fmt.Fprintf(w.out, "%s %s", w.prefix, line)
+}
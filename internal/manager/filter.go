@@ -0,0 +1,120 @@
+package manager
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/vedantprajapati/Grove/internal/config"
+	"github.com/vedantprajapati/Grove/internal/git"
+)
+
+// RepoFilter selects which repos of a set a multi-repo command acts on.
+// Only and Exclude take repo names or glob patterns (e.g. "svc-*").
+type RepoFilter struct {
+	Only    []string
+	Exclude []string
+	Dirty   bool // Only repos with uncommitted changes
+	Changed bool // Only repos with commits beyond their base branch
+}
+
+// IsZero reports whether the filter selects every repo.
+func (f RepoFilter) IsZero() bool {
+	return len(f.Only) == 0 && len(f.Exclude) == 0 && !f.Dirty && !f.Changed
+}
+
+// Validate checks that every pattern is a well-formed glob.
+func (f RepoFilter) Validate() error {
+	for _, p := range append(append([]string{}, f.Only...), f.Exclude...) {
+		if _, err := path.Match(p, ""); err != nil {
+			return fmt.Errorf("invalid repo pattern '%s': %v", p, err)
+		}
+	}
+	return nil
+}
+
+func (f RepoFilter) String() string {
+	var parts []string
+	if len(f.Only) > 0 {
+		parts = append(parts, "only "+strings.Join(f.Only, ","))
+	}
+	if len(f.Exclude) > 0 {
+		parts = append(parts, "excluding "+strings.Join(f.Exclude, ","))
+	}
+	if f.Dirty {
+		parts = append(parts, "dirty")
+	}
+	if f.Changed {
+		parts = append(parts, "changed")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// selectRepos applies m.Filter to the repos of a feature, keeping set order.
+func (m *Manager) selectRepos(feat config.Feature, repos []string) ([]string, error) {
+	if m.Filter.IsZero() {
+		return repos, nil
+	}
+	if err := m.Filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	var selected []string
+	for _, url := range repos {
+		repoName := git.GetRepoNameFromURL(url)
+		if len(m.Filter.Only) > 0 && !matchAny(m.Filter.Only, repoName) {
+			continue
+		}
+		if matchAny(m.Filter.Exclude, repoName) {
+			continue
+		}
+
+		repoPath := filepath.Join(feat.Path, repoName)
+		if m.Filter.Dirty {
+			if dirty, _, err := git.GetStatus(repoPath); err != nil || !dirty {
+				continue
+			}
+		}
+		if m.Filter.Changed && !m.branchChanged(feat, repoPath, repoName) {
+			continue
+		}
+		selected = append(selected, url)
+	}
+	return selected, nil
+}
+
+// branchChanged reports whether a worktree's branch has commits beyond its
+// base branch, the same test as RepoStatus.Changed. A base that has moved on
+// since the branch was created does not count as a change.
+func (m *Manager) branchChanged(feat config.Feature, repoPath, repoName string) bool {
+	base := m.baseBranch(feat, repoName)
+	if base == "" {
+		return false
+	}
+	ahead, _, err := git.AheadBehind(repoPath, git.ResolveBase(repoPath, base))
+	return err == nil && ahead > 0
+}
+
+// baseBranch returns the branch a feature's worktree of a repo was created
+// from. Features created before bases were recorded fall back to the default
+// branch of the repo's bare cache.
+func (m *Manager) baseBranch(feat config.Feature, repoName string) string {
+	if base := feat.Bases[repoName]; base != "" {
+		return base
+	}
+	branch, err := git.DefaultBranch(filepath.Join(m.CacheDir, repoName))
+	if err != nil {
+		return ""
+	}
+	return branch
+}
+
+func matchAny(patterns []string, name string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,108 @@
+package manager
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/vedantprajapati/Grove/internal/git"
+)
+
+// GrepOptions are passed through to git grep.
+type GrepOptions struct {
+	IgnoreCase bool     // -i
+	WordRegexp bool     // -w
+	Count      bool     // Only count matching lines per file
+	Pathspecs  []string // Limit the search to these paths in each repo
+}
+
+// GrepFile is one file with matches. Path is prefixed with the repo name.
+type GrepFile struct {
+	Repo    string      `json:"repo"`
+	Path    string      `json:"path"`
+	Count   int         `json:"count"`
+	Matches []GrepMatch `json:"matches,omitempty"` // Empty with GrepOptions.Count
+}
+
+// GrepMatch is a matching line.
+type GrepMatch struct {
+	Line int    `json:"line"`
+	Text string `json:"text"`
+}
+
+// GrepFeature runs git grep in every selected repo of a feature in parallel
+// and returns the files with matches, in set order. Only tracked files are
+// searched and binary files are skipped. Repos where git grep fails are
+// reported in the returned errors map.
+func (m *Manager) GrepFeature(featureName, pattern string, opts GrepOptions) ([]GrepFile, map[string]error, error) {
+	feat, repos, err := m.featureRepos(featureName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	args := []string{"--null", "--no-color", "-I"}
+	if opts.Count {
+		args = append(args, "--count")
+	} else {
+		args = append(args, "--line-number")
+	}
+	if opts.IgnoreCase {
+		args = append(args, "--ignore-case")
+	}
+	if opts.WordRegexp {
+		args = append(args, "--word-regexp")
+	}
+	args = append(append(args, "-e", pattern, "--"), opts.Pathspecs...)
+
+	perRepo := make([][]GrepFile, len(repos))
+	errs := make([]error, len(repos))
+	forEachRepo(repos, m.jobs(), func(i int, url string) {
+		repoName := git.GetRepoNameFromURL(url)
+		out, err := git.Grep(filepath.Join(feat.Path, repoName), args...)
+		if err != nil {
+			errs[i] = err
+			return
+		}
+		perRepo[i] = parseGrep(out, repoName, opts.Count)
+	})
+
+	var files []GrepFile
+	failed := make(map[string]error)
+	for i, url := range repos {
+		if errs[i] != nil {
+			failed[git.GetRepoNameFromURL(url)] = errs[i]
+			continue
+		}
+		files = append(files, perRepo[i]...)
+	}
+	return files, failed, nil
+}
+
+// parseGrep parses `git grep --null` output: "<path>\0<line>\0<text>" lines,
+// or "<path>\0<count>" lines with --count. Matches of a file are adjacent.
+func parseGrep(out, repoName string, count bool) []GrepFile {
+	var files []GrepFile
+	for _, line := range strings.Split(strings.TrimRight(out, "\n"), "\n") {
+		parts := strings.SplitN(line, "\x00", 3)
+		if len(parts) < 2 {
+			continue
+		}
+		path := repoName + "/" + parts[0]
+		if count {
+			n, _ := strconv.Atoi(parts[1])
+			files = append(files, GrepFile{Repo: repoName, Path: path, Count: n})
+			continue
+		}
+		if len(parts) != 3 {
+			continue
+		}
+		if len(files) == 0 || files[len(files)-1].Path != path {
+			files = append(files, GrepFile{Repo: repoName, Path: path})
+		}
+		f := &files[len(files)-1]
+		n, _ := strconv.Atoi(parts[1])
+		f.Matches = append(f.Matches, GrepMatch{Line: n, Text: parts[2]})
+		f.Count++
+	}
+	return files
+}
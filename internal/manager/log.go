@@ -0,0 +1,108 @@
+package manager
+
+import (
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vedantprajapati/Grove/internal/git"
+)
+
+// LogOptions filters the commits returned by LogFeature. Since, Author and
+// Grep are passed to git log as --since, --author and --grep.
+type LogOptions struct {
+	Full   bool // Include history from before the feature left its base branch
+	Since  string
+	Author string
+	Grep   string
+	Limit  int // Maximum number of commits overall; 0 for no limit
+}
+
+// LogEntry is a commit tagged with the repo it belongs to.
+type LogEntry struct {
+	Repo string `json:"repo"`
+	git.CommitInfo
+}
+
+// LogFeature merges the commits of every selected repo's feature branch into
+// one list, newest first. By default only commits since the base branch are
+// included. Repos whose log fails are reported in the returned errors map.
+func (m *Manager) LogFeature(featureName string, opts LogOptions) ([]LogEntry, map[string]error, error) {
+	feat, repos, err := m.featureRepos(featureName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	perRepo := make([][]LogEntry, len(repos))
+	errs := make([]error, len(repos))
+	forEachRepo(repos, m.jobs(), func(i int, url string) {
+		repoName := git.GetRepoNameFromURL(url)
+		repoPath := filepath.Join(feat.Path, repoName)
+
+		rev := "HEAD"
+		if base := m.baseBranch(feat, repoName); base != "" && !opts.Full {
+			rev = git.ResolveBase(repoPath, base) + "..HEAD"
+		}
+		perRepo[i], errs[i] = repoLog(repoPath, repoName, rev, opts)
+	})
+
+	var entries []LogEntry
+	failed := make(map[string]error)
+	for i, url := range repos {
+		if errs[i] != nil {
+			failed[git.GetRepoNameFromURL(url)] = errs[i]
+			continue
+		}
+		entries = append(entries, perRepo[i]...)
+	}
+
+	// Newest first; repos keep set order for commits made in the same second.
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Time.After(entries[j].Time) })
+	if opts.Limit > 0 && len(entries) > opts.Limit {
+		entries = entries[:opts.Limit]
+	}
+	return entries, failed, nil
+}
+
+func repoLog(repoPath, repoName, rev string, opts LogOptions) ([]LogEntry, error) {
+	args := []string{"log", "--format=%H%x00%an%x00%ct%x00%s"}
+	if opts.Since != "" {
+		args = append(args, "--since="+opts.Since)
+	}
+	if opts.Author != "" {
+		args = append(args, "--author="+opts.Author)
+	}
+	if opts.Grep != "" {
+		args = append(args, "--grep="+opts.Grep)
+	}
+	if opts.Limit > 0 {
+		args = append(args, "--max-count="+strconv.Itoa(opts.Limit))
+	}
+	args = append(args, rev, "--")
+
+	out, err := git.RunGitRaw(repoPath, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []LogEntry
+	for _, line := range strings.Split(strings.TrimRight(out, "\n"), "\n") {
+		parts := strings.SplitN(line, "\x00", 4)
+		if len(parts) != 4 {
+			continue
+		}
+		unix, _ := strconv.ParseInt(parts[2], 10, 64)
+		entries = append(entries, LogEntry{
+			Repo: repoName,
+			CommitInfo: git.CommitInfo{
+				SHA:     parts[0],
+				Author:  parts[1],
+				Time:    time.Unix(unix, 0),
+				Subject: parts[3],
+			},
+		})
+	}
+	return entries, nil
+}
@@ -1,45 +1,97 @@
 package manager
 
 import (
+	"context"
 	"fmt"
 	"github.com/vedantprajapati/Grove/internal/config"
 	"github.com/vedantprajapati/Grove/internal/git"
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
-	"sync"
+	"time"
 )
 
 type Manager struct {
 	Config   *config.Config
-	CacheDir string // Directory for bare repos
+	RootDir  string     // Overrides the config's root_dir when set
+	CacheDir string     // Directory for bare repos
+	DataDir  string     // Directory for Grove state (watch state, etc.)
+	Jobs     int        // Max repos processed at once; 0 uses the config default
+	Filter   RepoFilter // Repos acted on by exec, sync, status and push
+	Out      io.Writer  // Progress messages; defaults to os.Stdout
 }
 
+// Options override where a Manager finds its files. Empty fields fall back to
+// the GROVE_* environment variables and then to config.DefaultPaths.
+type Options struct {
+	ConfigPath string // Config file (GROVE_CONFIG)
+	RootDir    string // Where feature worktrees are created (GROVE_ROOT_DIR)
+	CacheDir   string // Bare repo cache (GROVE_CACHE_DIR)
+	ProjectDir string // Layer the project config nearest to this directory; "" for none
+}
+
+// NewManager loads the global config with the project config nearest to the
+// working directory, if any, layered over it.
 func NewManager() (*Manager, error) {
-	cfg, err := config.LoadConfig("")
-	if err != nil {
-		return nil, err
-	}
-	home, _ := os.UserHomeDir()
-	return &Manager{
-		Config:   cfg,
-		CacheDir: filepath.Join(home, ".grove", "cache"),
-	}, nil
+	wd, _ := os.Getwd()
+	return NewManagerWithOptions(Options{ProjectDir: wd})
 }
 
+// NewManagerWithConfig loads the config file at path, without a project layer.
 func NewManagerWithConfig(path string) (*Manager, error) {
-	cfg, err := config.LoadConfig(path)
+	return NewManagerWithOptions(Options{ConfigPath: path})
+}
+
+func NewManagerWithOptions(opts Options) (*Manager, error) {
+	paths, err := config.DefaultPaths()
 	if err != nil {
 		return nil, err
 	}
-	home, _ := os.UserHomeDir()
+	cfg, err := config.LoadConfig(firstNonEmpty(opts.ConfigPath, paths.Config))
+	if err != nil {
+		return nil, err
+	}
+	if opts.ProjectDir != "" {
+		if _, err := cfg.ApplyProject(opts.ProjectDir); err != nil {
+			return nil, err
+		}
+	}
 	return &Manager{
 		Config:   cfg,
-		CacheDir: filepath.Join(home, ".grove", "cache"),
+		RootDir:  firstNonEmpty(opts.RootDir, os.Getenv(config.EnvRootDir)),
+		CacheDir: firstNonEmpty(opts.CacheDir, paths.Cache),
+		DataDir:  paths.Data,
 	}, nil
 }
 
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// Root returns the directory feature worktrees are created in: RootDir if
+// set, otherwise the config's root_dir.
+func (m *Manager) Root() string {
+	if m.RootDir != "" {
+		return m.RootDir
+	}
+	return m.Config.RootDir
+}
+
+// out returns the writer for progress messages.
+func (m *Manager) out() io.Writer {
+	if m.Out == nil {
+		return os.Stdout
+	}
+	return m.Out
+}
+
 func (m *Manager) SaveConfig() error {
 	return m.Config.Save()
 }
@@ -47,34 +99,36 @@ func (m *Manager) SaveConfig() error {
 // --- Set Operations ---
 
 func (m *Manager) AddSet(name string, repos []string) error {
-	if _, exists := m.Config.Sets[name]; exists {
-		return fmt.Errorf("set '%s' already exists", name)
-	}
-
-	home, _ := os.UserHomeDir()
-	skillsDir := filepath.Join(home, ".grove", "skills", name)
+	skillsDir := filepath.Join(m.DataDir, "skills", name)
 
-	m.Config.Sets[name] = config.Set{
-		Repos:     repos,
-		SkillsDir: skillsDir,
-	}
-	return m.SaveConfig()
+	return m.Config.Update(func(cfg *config.Config) error {
+		if _, exists := cfg.Sets[name]; exists {
+			return fmt.Errorf("set '%s' already exists", name)
+		}
+		cfg.Sets[name] = config.Set{
+			Repos:     repos,
+			SkillsDir: skillsDir,
+		}
+		return nil
+	})
 }
 
 func (m *Manager) RemoveSet(name string) error {
-	if _, exists := m.Config.Sets[name]; !exists {
-		return fmt.Errorf("set '%s' not found", name)
-	}
+	return m.Config.Update(func(cfg *config.Config) error {
+		if _, exists := cfg.Sets[name]; !exists {
+			return fmt.Errorf("set '%s' not found", name)
+		}
 
-	// Check if any feature uses this set
-	for _, feat := range m.Config.Features {
-		if feat.Set == name {
-			return fmt.Errorf("cannot remove set '%s': used by active feature", name)
+		// Check if any feature uses this set
+		for _, feat := range cfg.Features {
+			if feat.Set == name {
+				return fmt.Errorf("cannot remove set '%s': used by active feature", name)
+			}
 		}
-	}
 
-	delete(m.Config.Sets, name)
-	return m.SaveConfig()
+		delete(cfg.Sets, name)
+		return nil
+	})
 }
 
 // --- Feature Operations ---
@@ -90,7 +144,7 @@ func (m *Manager) CreateFeature(setName, featureName string) error {
 	}
 
 	// Prepare directories
-	rootDir, err := config.ExpandPath(m.Config.RootDir)
+	rootDir, err := config.ExpandPath(m.Root())
 	if err != nil {
 		return err
 	}
@@ -103,42 +157,35 @@ func (m *Manager) CreateFeature(setName, featureName string) error {
 
 	cacheDir := m.CacheDir
 
-	fmt.Printf("Creating feature '%s' for set '%s' at %s...\n", featureName, setName, featurePath)
+	fmt.Fprintf(m.out(), "Creating feature '%s' for set '%s' at %s...\n", featureName, setName, featurePath)
 
 	if err := os.MkdirAll(featurePath, 0755); err != nil {
 		return fmt.Errorf("failed to create feature directory: %v", err)
 	}
 
-	// 1. Git Operations (Parallel)
-	var wg sync.WaitGroup
-	errChan := make(chan error, len(set.Repos))
-
-	for _, repoURL := range set.Repos {
-		wg.Add(1)
-		go func(url string) {
-			defer wg.Done()
-			bareRepo, err := git.EnsureBareRepo(url, cacheDir)
-			if err != nil {
-				errChan <- fmt.Errorf("failed to ensure bare repo for %s: %v", url, err)
-				return
-			}
+	// 1. Git Operations (Parallel, bounded by the worker pool)
+	errs := make([]error, len(set.Repos))
+	bases := make([]string, len(set.Repos))
+	forEachRepo(set.Repos, m.jobs(), func(i int, url string) {
+		bareRepo, err := git.EnsureBareRepo(url, cacheDir)
+		if err != nil {
+			errs[i] = fmt.Errorf("failed to ensure bare repo for %s: %v", url, err)
+			return
+		}
 
-			repoName := git.GetRepoNameFromURL(url)
-			targetPath := filepath.Join(featurePath, repoName)
+		repoName := git.GetRepoNameFromURL(url)
+		targetPath := filepath.Join(featurePath, repoName)
 
-			fmt.Printf("Adding worktree for %s...\n", repoName)
-			if err := git.CreateWorktree(bareRepo, featureName, targetPath); err != nil {
-				errChan <- err
-				return
-			}
-		}(repoURL)
-	}
+		// Worktrees start from the cache's default branch; remember it as the
+		// base so status can tell how far the feature has drifted from it.
+		bases[i], _ = git.DefaultBranch(bareRepo)
 
-	wg.Wait()
-	close(errChan)
+		fmt.Fprintf(m.out(), "Adding worktree for %s...\n", repoName)
+		errs[i] = git.CreateWorktree(bareRepo, featureName, targetPath)
+	})
 
-	// Collect first error if any
-	for err := range errChan {
+	// Return the first error in set order, if any
+	for _, err := range errs {
 		if err != nil {
 			return err
 		}
@@ -146,103 +193,245 @@ func (m *Manager) CreateFeature(setName, featureName string) error {
 
 	// 2. Skills Initialization
 	if err := m.initSkills(set, rootDir, setName); err != nil {
-		fmt.Printf("Warning: Failed to init skills: %v\n", err)
+		fmt.Fprintf(m.out(), "Warning: Failed to init skills: %v\n", err)
 	}
 
 	// 3. Update Config
-	m.Config.Features[featureName] = config.Feature{
-		Path: featurePath,
-		Set:  setName,
+	feat := config.Feature{
+		Path:  featurePath,
+		Set:   setName,
+		Bases: make(map[string]string),
+	}
+	for i, url := range set.Repos {
+		if bases[i] != "" {
+			feat.Bases[git.GetRepoNameFromURL(url)] = bases[i]
+		}
 	}
-
-	return m.SaveConfig()
+	return m.Config.Update(func(cfg *config.Config) error {
+		if _, exists := cfg.Features[featureName]; exists {
+			return fmt.Errorf("feature '%s' already exists", featureName)
+		}
+		cfg.Features[featureName] = feat
+		return nil
+	})
 }
 
-func (m *Manager) SyncFeature(featureName string) error {
-	feat, ok := m.Config.Features[featureName]
-	if !ok {
-		return fmt.Errorf("feature '%s' not found", featureName)
+// SelectFeatures returns the names of every feature, or only those in setName
+// when it is not empty, sorted by name.
+func (m *Manager) SelectFeatures(setName string) ([]string, error) {
+	if setName != "" {
+		if _, ok := m.Config.Sets[setName]; !ok {
+			return nil, fmt.Errorf("set '%s' not found", setName)
+		}
 	}
 
-	set, ok := m.Config.Sets[feat.Set]
-	if !ok {
-		return fmt.Errorf("set '%s' not found for feature", feat.Set)
+	var names []string
+	for name, feat := range m.Config.Features {
+		if setName == "" || feat.Set == setName {
+			names = append(names, name)
+		}
 	}
+	sort.Strings(names)
+	return names, nil
+}
 
-	fmt.Printf("Syncing feature '%s' (Set: %s) in parallel...\n", featureName, feat.Set)
+// RepoResult is the outcome of syncing or pushing a single repo.
+type RepoResult struct {
+	Feature string `json:"feature"`
+	Repo    string `json:"repo"`
+	OK      bool   `json:"ok"`
+	Error   string `json:"error,omitempty"`
+}
 
-	var wg sync.WaitGroup
-	errChan := make(chan error, len(set.Repos))
+// SyncFeatures syncs several features one after another, continuing past
+// failures until ctx is cancelled.
+func (m *Manager) SyncFeatures(ctx context.Context, featureNames []string) ([]RepoResult, error) {
+	var all []RepoResult
+	var failed []string
+	for _, name := range featureNames {
+		if ctx.Err() != nil {
+			return all, ctx.Err()
+		}
+		results, err := m.SyncFeatureContext(ctx, name)
+		all = append(all, results...)
+		if err != nil {
+			fmt.Fprintf(m.out(), "%v\n", err)
+			failed = append(failed, name)
+		}
+	}
 
-	for _, repoURL := range set.Repos {
-		wg.Add(1)
-		go func(url string) {
-			defer wg.Done()
-			repoName := git.GetRepoNameFromURL(url)
-			repoPath := filepath.Join(feat.Path, repoName)
-			if err := git.SyncRepo(repoPath); err != nil {
-				errChan <- fmt.Errorf("error syncing %s: %v", repoName, err)
-			} else {
-				fmt.Printf("  %s synced.\n", repoName)
-			}
-		}(repoURL)
+	if len(failed) > 0 {
+		return all, fmt.Errorf("sync failed for features: %s", strings.Join(failed, ", "))
 	}
+	return all, nil
+}
 
-	wg.Wait()
-	close(errChan)
+func (m *Manager) SyncFeature(featureName string) error {
+	_, err := m.SyncFeatureContext(context.Background(), featureName)
+	return err
+}
 
-	var errors []string
-	for err := range errChan {
-		errors = append(errors, err.Error())
-	}
+// SyncFeatureContext syncs every selected repo of a feature. Cancelling ctx
+// with an Interrupted cause forwards the signal to git; otherwise git is killed.
+func (m *Manager) SyncFeatureContext(ctx context.Context, featureName string) ([]RepoResult, error) {
+	return m.eachFeatureRepo(featureName, "sync", func(repoPath string) error {
+		return git.SyncRepoContext(ctx, repoPath)
+	})
+}
 
-	if len(errors) > 0 {
-		return fmt.Errorf("sync failed for some repositories:\n%s", strings.Join(errors, "\n"))
-	}
+// PushFeature pushes the feature branch of every selected repo to origin.
+func (m *Manager) PushFeature(featureName string) error {
+	_, err := m.PushFeatureContext(context.Background(), featureName)
+	return err
+}
 
-	return nil
+// PushFeatureContext is PushFeature, also returning the outcome of each repo.
+// Cancelling ctx stops git as for SyncFeatureContext.
+func (m *Manager) PushFeatureContext(ctx context.Context, featureName string) ([]RepoResult, error) {
+	return m.eachFeatureRepo(featureName, "push", func(repoPath string) error {
+		return git.PushRepoContext(ctx, repoPath)
+	})
 }
 
-func (m *Manager) ExecFeature(featureName string, command string, args []string) error {
+// featureRepos looks up a feature and the URLs of its repos that pass the filter.
+func (m *Manager) featureRepos(featureName string) (config.Feature, []string, error) {
 	feat, ok := m.Config.Features[featureName]
 	if !ok {
-		return fmt.Errorf("feature '%s' not found", featureName)
+		return feat, nil, fmt.Errorf("feature '%s' not found", featureName)
 	}
 
 	set, ok := m.Config.Sets[feat.Set]
 	if !ok {
-		return fmt.Errorf("set '%s' not found for feature", feat.Set)
+		return feat, nil, fmt.Errorf("set '%s' not found for feature", feat.Set)
 	}
 
-	fmt.Printf("Executing '%s %s' across %d repos...\n", command, strings.Join(args, " "), len(set.Repos))
+	repos, err := m.selectRepos(feat, set.Repos)
+	return feat, repos, err
+}
 
-	var wg sync.WaitGroup
-	for _, repoURL := range set.Repos {
-		wg.Add(1)
-		go func(url string) {
-			defer wg.Done()
-			repoName := git.GetRepoNameFromURL(url)
-			repoPath := filepath.Join(feat.Path, repoName)
+// eachFeatureRepo runs fn in every selected repo of a feature through the
+// worker pool, reporting progress in set order. verb names the action in
+// messages ("sync", "push").
+func (m *Manager) eachFeatureRepo(featureName, verb string, fn func(repoPath string) error) ([]RepoResult, error) {
+	feat, repos, err := m.featureRepos(featureName)
+	if err != nil {
+		return nil, err
+	}
+	if len(repos) == 0 {
+		fmt.Fprintf(m.out(), "No repos in feature '%s' match the filter (%s).\n", featureName, m.Filter)
+		return nil, nil
+	}
 
-			fmt.Printf("\n--- [%s] ---\n", repoName)
-			output, err := git.RunCommand(repoPath, command, args...)
-			if err != nil {
-				fmt.Printf("Error in %s: %v\nOutput: %s\n", repoName, err, output)
-			} else {
-				fmt.Println(output)
+	fmt.Fprintf(m.out(), "%sing feature '%s' (Set: %s) with %d jobs...\n", strings.ToUpper(verb[:1])+verb[1:], featureName, feat.Set, m.jobs())
+
+	results := make([]RepoResult, len(repos))
+	ordered := newOrderedFlush()
+	forEachRepo(repos, m.jobs(), func(i int, url string) {
+		repoName := git.GetRepoNameFromURL(url)
+		repoPath := filepath.Join(feat.Path, repoName)
+		results[i] = RepoResult{Feature: featureName, Repo: repoName, OK: true}
+		if err := fn(repoPath); err != nil {
+			results[i].OK = false
+			results[i].Error = fmt.Sprintf("error %sing %s: %v", verb, repoName, err)
+		}
+		ordered.Done(i, func() {
+			if results[i].OK {
+				fmt.Fprintf(m.out(), "  %s %sed.\n", repoName, verb)
 			}
-		}(repoURL)
+		})
+	})
+
+	var errors []string
+	for _, r := range results {
+		if !r.OK {
+			errors = append(errors, r.Error)
+		}
 	}
 
-	wg.Wait()
-	return nil
+	if len(errors) > 0 {
+		return results, fmt.Errorf("%s failed for some repositories:\n%s", verb, strings.Join(errors, "\n"))
+	}
+
+	return results, nil
 }
 
 type RepoStatus struct {
-	Name    string
-	Branch  string
-	IsDirty bool
-	ABC     string // Ahead/Behind Count
+	Name        string          `json:"name"`
+	Branch      string          `json:"branch"`  // "HEAD" when detached
+	Missing     bool            `json:"missing"` // Worktree directory does not exist
+	IsDirty     bool            `json:"dirty"`
+	Detached    bool            `json:"detached"`
+	HasUpstream bool            `json:"has_upstream"`
+	Ahead       int             `json:"ahead"`  // Commits on the branch but not its upstream
+	Behind      int             `json:"behind"` // Commits on the upstream but not the branch
+	Base        string          `json:"base"`
+	BaseAhead   int             `json:"base_ahead"`  // Commits on the branch but not the base
+	BaseBehind  int             `json:"base_behind"` // Commits on the base but not the branch
+	Changed     bool            `json:"changed"`     // The branch has commits beyond base
+	Staged      int             `json:"staged"`
+	Unstaged    int             `json:"unstaged"`
+	Untracked   int             `json:"untracked"`
+	Conflicted  int             `json:"conflicted"`
+	Stashes     int             `json:"stashes"`
+	Operation   string          `json:"operation,omitempty"` // rebase, merge, cherry-pick, ... if stopped part way
+	LastCommit  *git.CommitInfo `json:"last_commit,omitempty"`
+	Error       string          `json:"error,omitempty"` // Set if git could not read the worktree
+}
+
+// ChangesLabel summarizes the working tree, e.g. "DIRTY +1 ~2 ?1 stash:1 REBASE".
+func (s RepoStatus) ChangesLabel() string {
+	switch {
+	case s.Missing:
+		return "MISSING"
+	case s.Error != "":
+		return "ERROR"
+	}
+
+	parts := []string{"CLEAN"}
+	if s.IsDirty {
+		parts[0] = "DIRTY"
+	}
+	for _, c := range []struct {
+		sign  string
+		count int
+	}{{"+", s.Staged}, {"~", s.Unstaged}, {"?", s.Untracked}, {"!", s.Conflicted}} {
+		if c.count > 0 {
+			parts = append(parts, fmt.Sprintf("%s%d", c.sign, c.count))
+		}
+	}
+	if s.Stashes > 0 {
+		parts = append(parts, fmt.Sprintf("stash:%d", s.Stashes))
+	}
+	if s.Operation != "" {
+		parts = append(parts, strings.ToUpper(s.Operation))
+	}
+	return strings.Join(parts, " ")
+}
+
+// SyncLabel formats the ahead/behind counts, e.g. "↑1 ↓0".
+func (s RepoStatus) SyncLabel() string {
+	switch {
+	case s.Missing || s.Error != "":
+		return "-"
+	case s.Detached:
+		return "detached"
+	case !s.HasUpstream:
+		return "no upstream"
+	}
+	return fmt.Sprintf("↑%d ↓%d", s.Ahead, s.Behind)
+}
+
+// BaseLabel formats the counts against the base branch, e.g. "main +3 -1",
+// noting when the branch has no commits of its own.
+func (s RepoStatus) BaseLabel() string {
+	if s.Missing || s.Error != "" || s.Base == "" {
+		return "-"
+	}
+	label := fmt.Sprintf("%s +%d -%d", s.Base, s.BaseAhead, s.BaseBehind)
+	if !s.Changed {
+		label += " unchanged"
+	}
+	return label
 }
 
 func (m *Manager) GetFeatureStatus(featureName string) ([]RepoStatus, error) {
@@ -256,36 +445,60 @@ func (m *Manager) GetFeatureStatus(featureName string) ([]RepoStatus, error) {
 		return nil, fmt.Errorf("set '%s' not found for feature", feat.Set)
 	}
 
-	var wg sync.WaitGroup
-	statusChan := make(chan RepoStatus, len(set.Repos))
+	repos, err := m.selectRepos(feat, set.Repos)
+	if err != nil {
+		return nil, err
+	}
 
-	for _, repoURL := range set.Repos {
-		wg.Add(1)
-		go func(url string) {
-			defer wg.Done()
-			repoName := git.GetRepoNameFromURL(url)
-			repoPath := filepath.Join(feat.Path, repoName)
+	statuses := make([]RepoStatus, len(repos))
+	forEachRepo(repos, m.jobs(), func(i int, url string) {
+		statuses[i] = m.repoStatus(feat, url)
+	})
+	return statuses, nil
+}
 
-			branch, _ := git.BranchName(repoPath)
-			isDirty, abc, _ := git.GetStatus(repoPath)
+// repoStatus collects the status of a single repo worktree in a feature.
+func (m *Manager) repoStatus(feat config.Feature, url string) RepoStatus {
+	repoName := git.GetRepoNameFromURL(url)
+	repoPath := filepath.Join(feat.Path, repoName)
 
-			statusChan <- RepoStatus{
-				Name:    repoName,
-				Branch:  branch,
-				IsDirty: isDirty,
-				ABC:     abc,
-			}
-		}(repoURL)
+	status := RepoStatus{Name: repoName}
+	if _, err := os.Stat(repoPath); os.IsNotExist(err) {
+		status.Missing = true
+		return status
 	}
 
-	wg.Wait()
-	close(statusChan)
-
-	var statuses []RepoStatus
-	for s := range statusChan {
-		statuses = append(statuses, s)
+	wt, err := git.Status(repoPath)
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+	status.Branch = wt.Branch
+	status.IsDirty = wt.Dirty()
+	status.Detached = wt.Detached
+	status.HasUpstream = wt.HasUpstream
+	status.Ahead = wt.Ahead
+	status.Behind = wt.Behind
+	status.Staged = wt.Staged
+	status.Unstaged = wt.Unstaged
+	status.Untracked = wt.Untracked
+	status.Conflicted = wt.Conflicted
+	status.Stashes = wt.Stashes
+	status.Operation = wt.Operation
+
+	if wt.Head != "" {
+		status.LastCommit, _ = git.LastCommit(repoPath)
+	}
+
+	status.Base = m.baseBranch(feat, repoName)
+	if status.Base != "" {
+		ahead, behind, err := git.AheadBehind(repoPath, git.ResolveBase(repoPath, status.Base))
+		if err == nil {
+			status.BaseAhead, status.BaseBehind = ahead, behind
+			status.Changed = ahead > 0
+		}
 	}
-	return statuses, nil
+	return status
 }
 
 func (m *Manager) RemoveFeature(featureName string) error {
@@ -294,25 +507,19 @@ func (m *Manager) RemoveFeature(featureName string) error {
 		return fmt.Errorf("feature '%s' not found", featureName)
 	}
 
-	fmt.Printf("Removing feature '%s'...\n", featureName)
+	fmt.Fprintf(m.out(), "Removing feature '%s'...\n", featureName)
 
 	// 1. Remove Worktrees (Parallel cleanup)
 	cacheDir := m.CacheDir
 	if set, ok := m.Config.Sets[feat.Set]; ok {
-		var wg sync.WaitGroup
-		for _, repoURL := range set.Repos {
-			wg.Add(1)
-			go func(url string) {
-				defer wg.Done()
-				repoName := git.GetRepoNameFromURL(url)
-				bareRepo := filepath.Join(cacheDir, repoName)
-				worktreeStr := filepath.Join(feat.Path, repoName)
-				if err := git.RemoveWorktree(bareRepo, worktreeStr); err != nil {
-					fmt.Printf("  Warning: failed to clean worktree for %s: %v\n", repoName, err)
-				}
-			}(repoURL)
-		}
-		wg.Wait()
+		forEachRepo(set.Repos, m.jobs(), func(i int, url string) {
+			repoName := git.GetRepoNameFromURL(url)
+			bareRepo := filepath.Join(cacheDir, repoName)
+			worktreeStr := filepath.Join(feat.Path, repoName)
+			if err := git.RemoveWorktree(bareRepo, worktreeStr); err != nil {
+				fmt.Fprintf(m.out(), "  Warning: failed to clean worktree for %s: %v\n", repoName, err)
+			}
+		})
 	}
 
 	// 2. Remove Directory
@@ -321,8 +528,78 @@ func (m *Manager) RemoveFeature(featureName string) error {
 	}
 
 	// 3. Update Config
-	delete(m.Config.Features, featureName)
-	return m.SaveConfig()
+	return m.Config.Update(func(cfg *config.Config) error {
+		delete(cfg.Features, featureName)
+		return nil
+	})
+}
+
+// ArchiveFeature removes a feature like RemoveFeature, but first keeps each
+// repo's branch in the bare cache as archive/<feature> so the work survives
+// the feature being recreated. Repos with uncommitted changes stop the archive.
+func (m *Manager) ArchiveFeature(featureName string) error {
+	feat, ok := m.Config.Features[featureName]
+	if !ok {
+		return fmt.Errorf("feature '%s' not found", featureName)
+	}
+	set, ok := m.Config.Sets[feat.Set]
+	if !ok {
+		return fmt.Errorf("set '%s' not found for feature", feat.Set)
+	}
+
+	for _, url := range set.Repos {
+		repoName := git.GetRepoNameFromURL(url)
+		wt, err := git.Status(filepath.Join(feat.Path, repoName))
+		if err != nil {
+			continue // Missing worktrees have nothing to lose
+		}
+		if wt.Dirty() {
+			return fmt.Errorf("cannot archive '%s': %s has uncommitted changes", featureName, repoName)
+		}
+	}
+
+	var bareRepos []string
+	for _, url := range set.Repos {
+		bareRepo := filepath.Join(m.CacheDir, git.GetRepoNameFromURL(url))
+		if ok, _ := git.BranchExists(bareRepo, featureName); ok {
+			bareRepos = append(bareRepos, bareRepo)
+		}
+	}
+
+	archive := archiveBranchName(featureName, bareRepos, time.Now())
+	for _, bareRepo := range bareRepos {
+		if _, err := git.RunGit(bareRepo, "branch", archive, featureName); err != nil {
+			return fmt.Errorf("failed to archive branch in %s: %v", filepath.Base(bareRepo), err)
+		}
+	}
+	fmt.Fprintf(m.out(), "Archived branches as '%s'.\n", archive)
+
+	return m.RemoveFeature(featureName)
+}
+
+// archiveBranchName returns archive/<feature>, or when an earlier archive of
+// the same name exists in any of the repos, a name with the time appended, so
+// archiving never overwrites an earlier archive.
+func archiveBranchName(featureName string, bareRepos []string, now time.Time) string {
+	taken := func(name string) bool {
+		for _, bareRepo := range bareRepos {
+			if ok, _ := git.BranchExists(bareRepo, name); ok {
+				return true
+			}
+		}
+		return false
+	}
+
+	name := "archive/" + featureName
+	if !taken(name) {
+		return name
+	}
+	stamped := name + "-" + now.Format("20060102-150405")
+	name = stamped
+	for i := 2; taken(name); i++ {
+		name = fmt.Sprintf("%s-%d", stamped, i)
+	}
+	return name
 }
 
 func (m *Manager) initSkills(set config.Set, rootDir, setName string) error {
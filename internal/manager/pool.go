@@ -0,0 +1,69 @@
+package manager
+
+import (
+	"runtime"
+	"sync"
+)
+
+// jobs returns how many repos may be processed at once.
+// An explicit Manager.Jobs wins over the config default; otherwise one job per CPU.
+func (m *Manager) jobs() int {
+	if m.Jobs > 0 {
+		return m.Jobs
+	}
+	if m.Config.Jobs > 0 {
+		return m.Config.Jobs
+	}
+	return runtime.NumCPU()
+}
+
+// forEachRepo calls fn for every repo URL with at most jobs calls running at once.
+// Repos are started in order, so jobs=1 runs them serially in set order.
+func forEachRepo(repos []string, jobs int, fn func(i int, url string)) {
+	if jobs <= 0 || jobs > len(repos) {
+		jobs = len(repos)
+	}
+
+	sem := make(chan struct{}, jobs)
+	var wg sync.WaitGroup
+	for i, url := range repos {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, url string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			fn(i, url)
+		}(i, url)
+	}
+	wg.Wait()
+}
+
+// orderedFlush runs per-repo output callbacks in set order as repos finish,
+// so results print in config.Set.Repos order rather than completion order.
+type orderedFlush struct {
+	mu      sync.Mutex
+	next    int
+	pending map[int]func()
+}
+
+func newOrderedFlush() *orderedFlush {
+	return &orderedFlush{pending: make(map[int]func())}
+}
+
+// Done records that repo i finished. flush runs once every earlier repo has flushed.
+func (o *orderedFlush) Done(i int, flush func()) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.pending[i] = flush
+	for {
+		f, ok := o.pending[o.next]
+		if !ok {
+			return
+		}
+		delete(o.pending, o.next)
+		f()
+		o.next++
+	}
+}
@@ -0,0 +1,324 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	runsDirName     = "runs"
+	runFileName     = "run.json"
+	maxSavedRuns    = 50 // Older runs are pruned when a new one starts
+	runIDTimeFormat = "20060102-150405"
+)
+
+// RunRecord describes a saved exec run.
+type RunRecord struct {
+	ID         string          `json:"id"`
+	Argv       []string        `json:"argv"`
+	Shell      bool            `json:"shell"`
+	NoTemplate bool            `json:"no_template,omitempty"`
+	Features   []string        `json:"features"`
+	Started    time.Time       `json:"started"`
+	Duration   time.Duration   `json:"duration"`
+	Results    []RunRepoResult `json:"results"`
+}
+
+// RunRepoResult is the saved outcome of one repo in a run.
+type RunRepoResult struct {
+	Feature   string        `json:"feature"`
+	Repo      string        `json:"repo"`
+	ExitCode  int           `json:"exit_code"`
+	Duration  time.Duration `json:"duration"`
+	Failed    bool          `json:"failed"`
+	TimedOut  bool          `json:"timed_out,omitempty"`
+	Cancelled bool          `json:"cancelled,omitempty"`
+	Log       string        `json:"log,omitempty"` // Relative to the run directory; empty if it could not be written
+}
+
+// CommandLine returns the command as the user typed it.
+func (r *RunRecord) CommandLine() string {
+	return strings.Join(r.Argv, " ")
+}
+
+// Failed returns the results of repos that did not succeed.
+func (r *RunRecord) Failed() []RunRepoResult {
+	var failed []RunRepoResult
+	for _, res := range r.Results {
+		if res.Failed {
+			failed = append(failed, res)
+		}
+	}
+	return failed
+}
+
+// runRecorder saves the output and results of one exec invocation,
+// which may span several features.
+type runRecorder struct {
+	dir    string
+	record RunRecord
+	mu     sync.Mutex
+	noLog  map[string]bool // Logs that could not be created, by runLogPath
+}
+
+func (m *Manager) runsDir() string {
+	return filepath.Join(m.DataDir, runsDirName)
+}
+
+// startRecorder starts recording a run. Recording is best effort: when the
+// run directory cannot be created the command still runs, unrecorded.
+func (m *Manager) startRecorder(argv []string, opts ExecOptions) *runRecorder {
+	rec, err := m.newRunRecorder(argv, opts)
+	if err != nil {
+		fmt.Fprintf(m.out(), "Warning: %v; this run will not be saved\n", err)
+		return nil
+	}
+	return rec
+}
+
+func (m *Manager) newRunRecorder(argv []string, opts ExecOptions) (*runRecorder, error) {
+	m.pruneRuns(maxSavedRuns - 1)
+
+	now := time.Now()
+	id := fmt.Sprintf("%s-%04x", now.Format(runIDTimeFormat), rand.Intn(0x10000))
+	dir := filepath.Join(m.runsDir(), id)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create run directory: %v", err)
+	}
+	return &runRecorder{
+		dir:   dir,
+		noLog: make(map[string]bool),
+		record: RunRecord{
+			ID:         id,
+			Argv:       argv,
+			Shell:      opts.Shell,
+			NoTemplate: opts.NoTemplate,
+			Started:    now,
+		},
+	}, nil
+}
+
+func runLogPath(feature, repo string) string {
+	return filepath.Join(feature, repo+".log")
+}
+
+// openLog creates the log file for a repo in the run. A log that cannot be
+// created is left out of the saved results.
+func (r *runRecorder) openLog(feature, repo string) (*os.File, error) {
+	path := filepath.Join(r.dir, runLogPath(feature, repo))
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	var f *os.File
+	if err == nil {
+		f, err = os.Create(path)
+	}
+	if err != nil {
+		r.mu.Lock()
+		r.noLog[runLogPath(feature, repo)] = true
+		r.mu.Unlock()
+		return nil, err
+	}
+	return f, nil
+}
+
+// finish writes the run's results to disk.
+func (r *runRecorder) finish(results []ExecResult) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rec := &r.record
+	rec.Duration = time.Since(rec.Started)
+	rec.Features = nil
+	rec.Results = nil
+	for _, res := range results {
+		if len(rec.Features) == 0 || rec.Features[len(rec.Features)-1] != res.Feature {
+			rec.Features = append(rec.Features, res.Feature)
+		}
+		saved := RunRepoResult{
+			Feature:   res.Feature,
+			Repo:      res.Repo,
+			ExitCode:  res.ExitCode,
+			Duration:  res.Duration,
+			Failed:    res.Err != nil,
+			TimedOut:  res.TimedOut,
+			Cancelled: res.Cancelled,
+		}
+		if log := runLogPath(res.Feature, res.Repo); !r.noLog[log] {
+			saved.Log = filepath.ToSlash(log)
+		}
+		rec.Results = append(rec.Results, saved)
+	}
+
+	data, err := json.MarshalIndent(rec, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(r.dir, runFileName), data, 0644)
+}
+
+// finishRun saves a recorded run and tells the user where to find it.
+func finishRun(rec *runRecorder, results []ExecResult, out io.Writer) {
+	if err := rec.finish(results); err != nil {
+		fmt.Fprintf(out, "Warning: failed to save run %s: %v\n", rec.record.ID, err)
+		return
+	}
+	fmt.Fprintf(out, "\nRun saved as %s (gr runs show %s)\n", rec.record.ID, rec.record.ID)
+}
+
+// ListRuns returns saved runs, most recent first.
+func (m *Manager) ListRuns() ([]*RunRecord, error) {
+	entries, err := os.ReadDir(m.runsDir())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var runs []*RunRecord
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		run, err := m.LoadRun(e.Name())
+		if err != nil {
+			continue // Still running or interrupted before it was saved
+		}
+		runs = append(runs, run)
+	}
+	sort.Slice(runs, func(i, j int) bool { return runs[i].Started.After(runs[j].Started) })
+	return runs, nil
+}
+
+// LoadRun reads a saved run by ID.
+func (m *Manager) LoadRun(id string) (*RunRecord, error) {
+	if id == "" || strings.ContainsAny(id, `/\`) || id == "." || id == ".." {
+		return nil, fmt.Errorf("invalid run id '%s'", id)
+	}
+	data, err := os.ReadFile(filepath.Join(m.runsDir(), id, runFileName))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("run '%s' not found", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var run RunRecord
+	if err := json.Unmarshal(data, &run); err != nil {
+		return nil, fmt.Errorf("run '%s' is corrupt: %v", id, err)
+	}
+	return &run, nil
+}
+
+// RunLog returns the saved output of one repo in a run.
+func (m *Manager) RunLog(run *RunRecord, res RunRepoResult) (string, error) {
+	if res.Log == "" {
+		return "", fmt.Errorf("its log could not be written during the run")
+	}
+	data, err := os.ReadFile(filepath.Join(m.runsDir(), run.ID, filepath.FromSlash(res.Log)))
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// RetryFailed reruns a saved run's command in only the repos that failed,
+// recording the retry as a new run.
+func (m *Manager) RetryFailed(ctx context.Context, id string, opts ExecOptions) ([]ExecResult, error) {
+	run, err := m.LoadRun(id)
+	if err != nil {
+		return nil, err
+	}
+	failed := run.Failed()
+	if len(failed) == 0 {
+		return nil, fmt.Errorf("run '%s' has no failed repos", id)
+	}
+	if err := opts.setDefaults(); err != nil {
+		return nil, err
+	}
+
+	// Group the failed repos by feature, keeping the original order.
+	var features []string
+	repos := make(map[string][]string)
+	for _, res := range failed {
+		if _, ok := repos[res.Feature]; !ok {
+			features = append(features, res.Feature)
+		}
+		repos[res.Feature] = append(repos[res.Feature], res.Repo)
+	}
+
+	fmt.Fprintf(opts.Stdout, "Retrying '%s' in %d failed repos from run %s...\n", run.CommandLine(), len(failed), id)
+
+	opts.Shell = run.Shell
+	opts.NoTemplate = run.NoTemplate
+	policy := opts.ExitPolicy
+	opts.ExitPolicy = ExitPolicyNever
+	if opts.Record {
+		// Every feature records into this one run, or none if it failed.
+		opts.recorder = m.startRecorder(run.Argv, opts)
+		opts.Record = false
+	}
+
+	savedFilter := m.Filter
+	defer func() { m.Filter = savedFilter }()
+
+	var all []ExecResult
+	for _, name := range features {
+		m.Filter = RepoFilter{Only: repos[name]}
+		if len(features) > 1 {
+			fmt.Fprintf(opts.Stdout, "\n=== %s ===\n", name)
+		}
+		results, err := m.ExecFeatureWithOptions(ctx, name, run.Argv[0], run.Argv[1:], opts)
+		if err != nil {
+			return all, err
+		}
+		all = append(all, results...)
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	if opts.recorder != nil {
+		finishRun(opts.recorder, all, opts.Stdout)
+	}
+	return all, execError(all, policy)
+}
+
+// pruneRuns deletes the oldest saved runs so at most keep remain.
+func (m *Manager) pruneRuns(keep int) {
+	entries, err := os.ReadDir(m.runsDir())
+	if err != nil {
+		return
+	}
+	var ids []string
+	for _, e := range entries {
+		if e.IsDir() {
+			ids = append(ids, e.Name())
+		}
+	}
+	// IDs start with a timestamp, so they sort oldest first.
+	sort.Strings(ids)
+	for len(ids) > keep {
+		os.RemoveAll(filepath.Join(m.runsDir(), ids[0]))
+		ids = ids[1:]
+	}
+}
+
+// lockedWriter serializes writes from a command's stdout and stderr copiers.
+type lockedWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (l *lockedWriter) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.w.Write(p)
+}
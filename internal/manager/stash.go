@@ -0,0 +1,311 @@
+package manager
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vedantprajapati/Grove/internal/git"
+)
+
+// stashIDFormat names a feature stash after the time it was pushed. A second
+// group pushed within the same second gets -2, -3, ... appended.
+const stashIDFormat = "20060102-150405"
+
+// StashGroup is a set of stashes pushed together across the repos of a
+// feature. Each repo's stash message starts with the group's label,
+// grove:<feature>:<id>.
+type StashGroup struct {
+	ID      string    `json:"id"`
+	Feature string    `json:"feature"`
+	Message string    `json:"message,omitempty"`
+	Created time.Time `json:"created"`
+	Repos   []string  `json:"repos"`
+}
+
+// StashResult is the outcome of a stash operation in one repo.
+type StashResult struct {
+	Repo       string `json:"repo"`
+	Skipped    string `json:"skipped,omitempty"`    // Why the repo was left alone: nothing to stash or no stash
+	Conflicted bool   `json:"conflicted,omitempty"` // Pop stopped on conflicts; the stash was kept
+	Error      string `json:"error,omitempty"`
+}
+
+func stashLabel(feature, id string) string {
+	return "grove:" + feature + ":" + id
+}
+
+// StashPush stashes the changes of every dirty repo of a feature under one
+// shared label and returns the id of the new group.
+func (m *Manager) StashPush(featureName, message string, untracked bool) (string, []StashResult, error) {
+	feat, repos, err := m.featureRepos(featureName)
+	if err != nil {
+		return "", nil, err
+	}
+
+	id := newStashID(time.Now(), stashIDs(feat.Path, repos, featureName))
+	label := stashLabel(featureName, id)
+	if message != "" {
+		label += " " + message
+	}
+
+	results := make([]StashResult, len(repos))
+	forEachRepo(repos, m.jobs(), func(i int, url string) {
+		repoName := git.GetRepoNameFromURL(url)
+		repoPath := filepath.Join(feat.Path, repoName)
+		results[i] = StashResult{Repo: repoName}
+
+		status, err := git.Status(repoPath)
+		if err != nil {
+			results[i].Error = err.Error()
+			return
+		}
+		if !status.Dirty() || (!untracked && status.Staged+status.Unstaged+status.Conflicted == 0) {
+			results[i].Skipped = "nothing to stash"
+			return
+		}
+		args := []string{"stash", "push", "--message", label}
+		if untracked {
+			args = append(args, "--include-untracked")
+		}
+		if _, err := git.RunGit(repoPath, args...); err != nil {
+			results[i].Error = err.Error()
+		}
+	})
+	return id, results, stashError("stash", results)
+}
+
+// StashList returns the stash groups of a feature, newest first.
+func (m *Manager) StashList(featureName string) ([]StashGroup, error) {
+	feat, repos, err := m.featureRepos(featureName)
+	if err != nil {
+		return nil, err
+	}
+
+	perRepo := make([]map[string]string, len(repos))
+	errs := make([]error, len(repos))
+	forEachRepo(repos, m.jobs(), func(i int, url string) {
+		repoPath := filepath.Join(feat.Path, git.GetRepoNameFromURL(url))
+		perRepo[i], errs[i] = featureStashes(repoPath, featureName)
+	})
+
+	groups := make(map[string]*StashGroup)
+	for i, url := range repos {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		repoName := git.GetRepoNameFromURL(url)
+		for id, message := range perRepo[i] {
+			g, ok := groups[id]
+			if !ok {
+				created, _ := time.ParseInLocation(stashIDFormat, stashIDTime(id), time.Local)
+				g = &StashGroup{ID: id, Feature: featureName, Message: message, Created: created}
+				groups[id] = g
+			}
+			g.Repos = append(g.Repos, repoName)
+		}
+	}
+
+	list := make([]StashGroup, 0, len(groups))
+	for _, g := range groups {
+		list = append(list, *g)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if ti, tj := stashIDTime(list[i].ID), stashIDTime(list[j].ID); ti != tj {
+			return ti > tj
+		}
+		return stashIDSeq(list[i].ID) > stashIDSeq(list[j].ID)
+	})
+	return list, nil
+}
+
+// stashIDs returns the ids of the feature's stash groups in any of its repos.
+// Repos whose stashes cannot be listed are left out.
+func stashIDs(featPath string, repos []string, featureName string) map[string]bool {
+	ids := make(map[string]bool)
+	for _, url := range repos {
+		stashes, _ := featureStashes(filepath.Join(featPath, git.GetRepoNameFromURL(url)), featureName)
+		for id := range stashes {
+			ids[id] = true
+		}
+	}
+	return ids
+}
+
+// newStashID returns the id for a group pushed at now that is not in taken.
+func newStashID(now time.Time, taken map[string]bool) string {
+	stamp := now.Format(stashIDFormat)
+	id := stamp
+	for n := 2; taken[id]; n++ {
+		id = fmt.Sprintf("%s-%d", stamp, n)
+	}
+	return id
+}
+
+// stashIDTime returns the timestamp part of a stash id.
+func stashIDTime(id string) string {
+	if len(id) > len(stashIDFormat) {
+		return id[:len(stashIDFormat)]
+	}
+	return id
+}
+
+// stashIDSeq returns the number a stash id was bumped to, or 1.
+func stashIDSeq(id string) int {
+	n, err := strconv.Atoi(strings.TrimPrefix(id[len(stashIDTime(id)):], "-"))
+	if err != nil {
+		return 1
+	}
+	return n
+}
+
+// StashPop restores a stash group in every repo that has it. An empty id
+// means the newest group. Every repo is attempted even if one conflicts;
+// conflicted repos keep their stash, as with git stash pop.
+func (m *Manager) StashPop(featureName, id string) ([]StashResult, error) {
+	return m.eachStash(featureName, id, "pop", func(repoPath, ref string, res *StashResult) {
+		if _, err := git.RunGit(repoPath, "stash", "pop", ref); err != nil {
+			if status, serr := git.Status(repoPath); serr == nil && status.Conflicted > 0 {
+				res.Conflicted = true
+				return
+			}
+			res.Error = err.Error()
+		}
+	})
+}
+
+// StashDrop deletes a stash group from every repo that has it. An empty id
+// means the newest group.
+func (m *Manager) StashDrop(featureName, id string) ([]StashResult, error) {
+	return m.eachStash(featureName, id, "drop", func(repoPath, ref string, res *StashResult) {
+		if _, err := git.RunGit(repoPath, "stash", "drop", ref); err != nil {
+			res.Error = err.Error()
+		}
+	})
+}
+
+func (m *Manager) eachStash(featureName, id, verb string, fn func(repoPath, ref string, res *StashResult)) ([]StashResult, error) {
+	if id == "" {
+		groups, err := m.StashList(featureName)
+		if err != nil {
+			return nil, err
+		}
+		if len(groups) == 0 {
+			return nil, fmt.Errorf("no stashes for feature '%s'", featureName)
+		}
+		id = groups[0].ID
+	}
+
+	feat, repos, err := m.featureRepos(featureName)
+	if err != nil {
+		return nil, err
+	}
+
+	label := stashLabel(featureName, id)
+	results := make([]StashResult, len(repos))
+	forEachRepo(repos, m.jobs(), func(i int, url string) {
+		repoName := git.GetRepoNameFromURL(url)
+		repoPath := filepath.Join(feat.Path, repoName)
+		results[i] = StashResult{Repo: repoName}
+
+		ref, err := findStash(repoPath, label)
+		switch {
+		case err != nil:
+			results[i].Error = err.Error()
+		case ref == "":
+			results[i].Skipped = "no stash"
+		default:
+			fn(repoPath, ref, &results[i])
+		}
+	})
+
+	found := false
+	for _, r := range results {
+		if r.Skipped == "" {
+			found = true
+		}
+	}
+	if !found {
+		return results, fmt.Errorf("stash '%s' not found in feature '%s'", id, featureName)
+	}
+	return results, stashError(verb, results)
+}
+
+// stashMessage returns the message of a stash subject, "On <branch>: <message>".
+// Branch names cannot contain a colon, so the first ": " ends the header.
+func stashMessage(subject string) string {
+	_, message, _ := strings.Cut(subject, ": ")
+	return message
+}
+
+// stashLines returns the "<ref>\x00<subject>" lines of a repo's stash list.
+func stashLines(repoPath string) ([]string, error) {
+	out, err := git.RunGit(repoPath, "stash", "list", "--format=%gd%x00%gs")
+	if err != nil || out == "" {
+		return nil, err
+	}
+	return strings.Split(out, "\n"), nil
+}
+
+// featureStashes maps the ids of a feature's stash groups in a repo to their
+// messages.
+func featureStashes(repoPath, featureName string) (map[string]string, error) {
+	lines, err := stashLines(repoPath)
+	if err != nil {
+		return nil, err
+	}
+	prefix := stashLabel(featureName, "")
+	stashes := make(map[string]string)
+	for _, line := range lines {
+		parts := strings.SplitN(line, "\x00", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		rest, ok := strings.CutPrefix(stashMessage(parts[1]), prefix)
+		if !ok {
+			continue
+		}
+		id, message, _ := strings.Cut(rest, " ")
+		stashes[id] = message
+	}
+	return stashes, nil
+}
+
+// findStash returns the stash@{n} ref whose message has label, or "".
+func findStash(repoPath, label string) (string, error) {
+	lines, err := stashLines(repoPath)
+	if err != nil {
+		return "", err
+	}
+	for _, line := range lines {
+		parts := strings.SplitN(line, "\x00", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		if message := stashMessage(parts[1]); message == label || strings.HasPrefix(message, label+" ") {
+			return parts[0], nil
+		}
+	}
+	return "", nil
+}
+
+// stashError summarizes the failed repos of a stash operation. Conflicts are
+// reported too, since the group is then only partly restored.
+func stashError(verb string, results []StashResult) error {
+	var errors []string
+	for _, r := range results {
+		switch {
+		case r.Error != "":
+			errors = append(errors, fmt.Sprintf("error in %s: %s", r.Repo, r.Error))
+		case r.Conflicted:
+			errors = append(errors, fmt.Sprintf("conflicts in %s; resolve them, then run 'git stash drop' there", r.Repo))
+		}
+	}
+	if len(errors) > 0 {
+		return fmt.Errorf("stash %s failed for some repositories:\n%s", verb, strings.Join(errors, "\n"))
+	}
+	return nil
+}
@@ -0,0 +1,239 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"text/template"
+	"time"
+
+	"github.com/vedantprajapati/Grove/internal/git"
+)
+
+// TaskReport is the outcome of running a named task across a feature.
+type TaskReport struct {
+	Task         string        `json:"task"`
+	Results      []ExecResult  `json:"results"`       // Repos that took part, in set order
+	Undefined    []string      `json:"undefined"`     // Repos with no command for the task
+	CriticalPath []string      `json:"critical_path"` // Longest chain of dependent repos, first to last
+	CriticalTime time.Duration `json:"critical_time"` // Sum of run times along CriticalPath
+	Wall         time.Duration `json:"wall"`
+}
+
+// taskNode is one repo taking part in a task run.
+type taskNode struct {
+	repo      string
+	index     int // Position among participating repos, for prefixes
+	templates []*template.Template
+	deps      []int
+}
+
+// RunTask runs a task defined on the feature's set. Each repo starts as soon
+// as the repos it depends on have succeeded, with at most Manager.Jobs running
+// at once; repos whose dependencies failed are skipped. Task commands run
+// through the user's shell and support the same templates as exec.
+func (m *Manager) RunTask(ctx context.Context, featureName, taskName string, opts ExecOptions) (*TaskReport, error) {
+	feat, ok := m.Config.Features[featureName]
+	if !ok {
+		return nil, fmt.Errorf("feature '%s' not found", featureName)
+	}
+
+	set, ok := m.Config.Sets[feat.Set]
+	if !ok {
+		return nil, fmt.Errorf("set '%s' not found for feature", feat.Set)
+	}
+
+	task, ok := set.Tasks[taskName]
+	if !ok {
+		return nil, fmt.Errorf("task '%s' not defined for set '%s'", taskName, feat.Set)
+	}
+
+	if err := opts.setDefaults(); err != nil {
+		return nil, err
+	}
+
+	inSet := make(map[string]bool)
+	for _, url := range set.Repos {
+		inSet[git.GetRepoNameFromURL(url)] = true
+	}
+	for repo, deps := range task.DependsOn {
+		for _, name := range append([]string{repo}, deps...) {
+			if !inSet[name] {
+				return nil, fmt.Errorf("task '%s' refers to unknown repo '%s'", taskName, name)
+			}
+		}
+	}
+
+	repos, err := m.selectRepos(feat, set.Repos)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &TaskReport{Task: taskName}
+	var nodes []*taskNode
+	var participating []string
+	byName := make(map[string]*taskNode)
+	for _, url := range repos {
+		repoName := git.GetRepoNameFromURL(url)
+		command, ok := task.Repos[repoName]
+		if !ok {
+			command = task.Command
+		}
+		if command == "" {
+			report.Undefined = append(report.Undefined, repoName)
+			continue
+		}
+
+		templates, err := parseArgTemplates([]string{command}, false)
+		if err != nil {
+			return nil, err
+		}
+		node := &taskNode{repo: repoName, index: len(nodes), templates: templates}
+		nodes = append(nodes, node)
+		participating = append(participating, url)
+		byName[repoName] = node
+	}
+
+	// Dependencies on repos that are not taking part are ignored.
+	for _, node := range nodes {
+		for _, dep := range task.DependsOn[node.repo] {
+			if d, ok := byName[dep]; ok {
+				node.deps = append(node.deps, d.index)
+			}
+		}
+	}
+	if cycle := findCycle(nodes); cycle != nil {
+		return nil, fmt.Errorf("task '%s' has a dependency cycle: %v", taskName, cycle)
+	}
+
+	if len(nodes) == 0 {
+		fmt.Fprintf(opts.Stdout, "No repos in feature '%s' define task '%s'.\n", featureName, taskName)
+		return report, nil
+	}
+
+	fmt.Fprintf(opts.Stdout, "Running task '%s' across %d repos (%d jobs)...\n", taskName, len(nodes), m.jobs())
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	run := newExecRun(featureName, feat, participating, opts, cancel)
+	results := make([]ExecResult, len(nodes))
+	ends := make([]time.Duration, len(nodes))
+	done := make([]chan struct{}, len(nodes))
+	for i := range done {
+		done[i] = make(chan struct{})
+	}
+
+	start := time.Now()
+	sem := make(chan struct{}, m.jobs())
+	for _, node := range nodes {
+		go func(node *taskNode) {
+			defer close(done[node.index])
+			for _, d := range node.deps {
+				<-done[d]
+			}
+			for _, d := range node.deps {
+				if results[d].Err != nil {
+					results[node.index] = ExecResult{
+						Feature:  featureName,
+						Repo:     node.repo,
+						ExitCode: -1,
+						Skipped:  true,
+						Err:      fmt.Errorf("dependency %s failed", nodes[d].repo),
+					}
+					return
+				}
+			}
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			results[node.index] = m.runRepo(ctx, run, node.index, node.repo, node.templates, true)
+			ends[node.index] = time.Since(start)
+		}(node)
+	}
+	for _, ch := range done {
+		<-ch
+	}
+
+	report.Wall = time.Since(start)
+	report.Results = results
+	report.CriticalPath, report.CriticalTime = criticalPath(nodes, results, ends)
+	return report, execError(results, opts.ExitPolicy)
+}
+
+// findCycle returns the repos forming a dependency cycle, or nil if there is none.
+func findCycle(nodes []*taskNode) []string {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make([]int, len(nodes))
+	var stack []string
+
+	var visit func(i int) []string
+	visit = func(i int) []string {
+		state[i] = visiting
+		stack = append(stack, nodes[i].repo)
+		for _, d := range nodes[i].deps {
+			switch state[d] {
+			case visiting:
+				for j, name := range stack {
+					if name == nodes[d].repo {
+						return append(append([]string{}, stack[j:]...), nodes[d].repo)
+					}
+				}
+			case unvisited:
+				if cycle := visit(d); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[i] = visited
+		return nil
+	}
+
+	for i := range nodes {
+		if state[i] == unvisited {
+			if cycle := visit(i); cycle != nil {
+				return cycle
+			}
+		}
+	}
+	return nil
+}
+
+// criticalPath follows the chain of dependencies that finished last, ending at
+// the repo that finished last overall.
+func criticalPath(nodes []*taskNode, results []ExecResult, ends []time.Duration) ([]string, time.Duration) {
+	last := -1
+	for i := range nodes {
+		if !results[i].Skipped && (last < 0 || ends[i] > ends[last]) {
+			last = i
+		}
+	}
+	if last < 0 {
+		return nil, 0
+	}
+
+	var path []string
+	var total time.Duration
+	for i := last; i >= 0; {
+		path = append(path, nodes[i].repo)
+		total += results[i].Duration
+
+		next := -1
+		for _, d := range nodes[i].deps {
+			if next < 0 || ends[d] > ends[next] {
+				next = d
+			}
+		}
+		i = next
+	}
+
+	// Reverse so the path reads from the first repo to the last.
+	for a, b := 0, len(path)-1; a < b; a, b = a+1, b-1 {
+		path[a], path[b] = path[b], path[a]
+	}
+	return path, total
+}
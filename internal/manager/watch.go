@@ -0,0 +1,330 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/vedantprajapati/Grove/internal/config"
+	"github.com/vedantprajapati/Grove/internal/git"
+)
+
+const watchStateFileName = "state.json"
+
+// WatchState is the snapshot written by `gr watch` and read by cached status lookups.
+type WatchState struct {
+	UpdatedAt time.Time              `json:"updated_at"`
+	Features  map[string][]RepoState `json:"features"`
+}
+
+// RepoState is the watched state of a single repo in a feature.
+type RepoState struct {
+	RepoStatus
+	BaseSHA     string `json:"base_sha"`
+	UpstreamSHA string `json:"upstream_sha"`
+}
+
+type WatchOptions struct {
+	Interval time.Duration
+	Once     bool   // Run a single refresh and return
+	OnChange string // Command run when a base branch or upstream moves; defaults to config watch_command
+}
+
+// watchEvent describes a ref that moved between two refreshes.
+type watchEvent struct {
+	Kind    string // "base" or "upstream"
+	Feature string
+	Repo    string
+	Path    string
+	From    string
+	To      string
+}
+
+func (m *Manager) watchStatePath() string {
+	return filepath.Join(m.DataDir, watchStateFileName)
+}
+
+// Watch periodically fetches the bare caches, recomputes the status of every
+// feature and writes it to the state file until ctx is cancelled. The config
+// is reloaded before each refresh, so features added or removed meanwhile
+// are picked up.
+func (m *Manager) Watch(ctx context.Context, opts WatchOptions) error {
+	if opts.Interval <= 0 {
+		opts.Interval = 5 * time.Minute
+	}
+
+	for {
+		err := m.refreshWatchState(opts.OnChange)
+		if opts.Once {
+			return err
+		}
+		if err != nil {
+			fmt.Fprintf(m.out(), "Warning: %v\n", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(opts.Interval):
+		}
+	}
+}
+
+// LoadWatchState reads the state file written by Watch.
+func (m *Manager) LoadWatchState() (*WatchState, error) {
+	data, err := os.ReadFile(m.watchStatePath())
+	if err != nil {
+		return nil, err
+	}
+	var state WatchState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, err
+	}
+	if state.Features == nil {
+		state.Features = make(map[string][]RepoState)
+	}
+	return &state, nil
+}
+
+// CachedFeatureStatus returns the status recorded by the last watch refresh
+// instead of querying git. Name and dirty filters apply; --changed needs git
+// and is ignored here.
+func (m *Manager) CachedFeatureStatus(featureName string) ([]RepoStatus, time.Time, error) {
+	if _, ok := m.Config.Features[featureName]; !ok {
+		return nil, time.Time{}, fmt.Errorf("feature '%s' not found", featureName)
+	}
+
+	state, err := m.LoadWatchState()
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("no cached state (is 'gr watch' running?): %v", err)
+	}
+	repos, ok := state.Features[featureName]
+	if !ok {
+		return nil, time.Time{}, fmt.Errorf("no cached state for feature '%s'", featureName)
+	}
+
+	statuses := make([]RepoStatus, 0, len(repos))
+	for _, r := range repos {
+		if len(m.Filter.Only) > 0 && !matchAny(m.Filter.Only, r.Name) {
+			continue
+		}
+		if matchAny(m.Filter.Exclude, r.Name) || (m.Filter.Dirty && !r.IsDirty) {
+			continue
+		}
+		statuses = append(statuses, r.RepoStatus)
+	}
+	return statuses, state.UpdatedAt, nil
+}
+
+func (m *Manager) refreshWatchState(onChange string) error {
+	if err := m.reloadConfig(); err != nil {
+		return fmt.Errorf("failed to reload config: %v", err)
+	}
+	if onChange == "" {
+		onChange = m.Config.WatchCommand
+	}
+
+	prev, err := m.LoadWatchState()
+	if err != nil {
+		prev = &WatchState{Features: make(map[string][]RepoState)}
+	}
+
+	m.fetchCaches()
+
+	state := &WatchState{
+		UpdatedAt: time.Now(),
+		Features:  make(map[string][]RepoState),
+	}
+
+	var names []string
+	for name, feat := range m.Config.Features {
+		if _, ok := m.Config.Sets[feat.Set]; ok {
+			names = append(names, name)
+		}
+	}
+
+	var mu sync.Mutex
+	forEachRepo(names, m.jobs(), func(i int, name string) {
+		feat := m.Config.Features[name]
+		set := m.Config.Sets[feat.Set]
+		repos := make([]RepoState, len(set.Repos))
+		for i, url := range set.Repos {
+			repos[i] = m.watchRepoState(feat, url)
+		}
+		mu.Lock()
+		state.Features[name] = repos
+		mu.Unlock()
+	})
+
+	if err := m.saveWatchState(state); err != nil {
+		return fmt.Errorf("failed to write watch state: %v", err)
+	}
+	fmt.Fprintf(m.out(), "[%s] Refreshed %d features.\n", state.UpdatedAt.Format("15:04:05"), len(state.Features))
+
+	for _, ev := range diffWatchStates(prev, state, m.Config.Features) {
+		fmt.Fprintf(m.out(), "  %s: %s moved in %s (%s -> %s)\n", ev.Feature, ev.Kind, ev.Repo, shortSHA(ev.From), shortSHA(ev.To))
+		if onChange != "" {
+			m.runWatchHook(onChange, ev)
+		}
+	}
+	return nil
+}
+
+// reloadConfig rereads the config file, layering the same project config
+// over it again.
+func (m *Manager) reloadConfig() error {
+	cfg, err := config.LoadConfig(m.Config.Path())
+	if err != nil {
+		return err
+	}
+	if project := m.Config.ProjectFile(); project != "" {
+		if _, err := cfg.ApplyProject(filepath.Dir(project)); err != nil {
+			return err
+		}
+	}
+	m.Config = cfg
+	return nil
+}
+
+// fetchCaches fetches every bare cache used by an active feature.
+func (m *Manager) fetchCaches() {
+	seen := make(map[string]bool)
+	var barePaths []string
+	for _, feat := range m.Config.Features {
+		set, ok := m.Config.Sets[feat.Set]
+		if !ok {
+			continue
+		}
+		for _, url := range set.Repos {
+			repoName := git.GetRepoNameFromURL(url)
+			if seen[repoName] {
+				continue
+			}
+			seen[repoName] = true
+
+			barePath := filepath.Join(m.CacheDir, repoName)
+			if _, err := os.Stat(barePath); os.IsNotExist(err) {
+				continue
+			}
+			barePaths = append(barePaths, barePath)
+		}
+	}
+
+	forEachRepo(barePaths, m.jobs(), func(i int, barePath string) {
+		if err := git.FetchBare(barePath); err != nil {
+			fmt.Fprintf(m.out(), "  Warning: failed to fetch %s: %v\n", filepath.Base(barePath), err)
+		}
+	})
+}
+
+func (m *Manager) watchRepoState(feat config.Feature, url string) RepoState {
+	status := m.repoStatus(feat, url)
+	repoPath := filepath.Join(feat.Path, status.Name)
+
+	state := RepoState{RepoStatus: status}
+	if status.Base != "" {
+		state.BaseSHA, _ = git.RevParse(repoPath, git.ResolveBase(repoPath, status.Base))
+	}
+	state.UpstreamSHA, _ = git.RevParse(repoPath, "@{u}")
+	return state
+}
+
+func (m *Manager) saveWatchState(state *WatchState) error {
+	if err := os.MkdirAll(m.DataDir, 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Write through a temp file so readers never see a partial state.
+	path := m.watchStatePath()
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// diffWatchStates reports base and upstream refs that moved since the previous refresh.
+// Repos seen for the first time produce no events.
+func diffWatchStates(prev, cur *WatchState, features map[string]config.Feature) []watchEvent {
+	var events []watchEvent
+	for name, repos := range cur.Features {
+		old := make(map[string]RepoState)
+		for _, r := range prev.Features[name] {
+			old[r.Name] = r
+		}
+		for _, r := range repos {
+			o, ok := old[r.Name]
+			if !ok {
+				continue
+			}
+			path := filepath.Join(features[name].Path, r.Name)
+			if o.BaseSHA != "" && r.BaseSHA != "" && o.BaseSHA != r.BaseSHA {
+				events = append(events, watchEvent{"base", name, r.Name, path, o.BaseSHA, r.BaseSHA})
+			}
+			if o.UpstreamSHA != "" && r.UpstreamSHA != "" && o.UpstreamSHA != r.UpstreamSHA {
+				events = append(events, watchEvent{"upstream", name, r.Name, path, o.UpstreamSHA, r.UpstreamSHA})
+			}
+		}
+	}
+	return events
+}
+
+// runWatchHook runs the configured change command through the shell.
+// Details of the event are passed as GROVE_* environment variables.
+func (m *Manager) runWatchHook(command string, ev watchEvent) {
+	c := shellCommand(command)
+	c.Dir = ev.Path
+	c.Env = append(os.Environ(),
+		"GROVE_EVENT="+ev.Kind,
+		"GROVE_FEATURE="+ev.Feature,
+		"GROVE_REPO="+ev.Repo,
+		"GROVE_PATH="+ev.Path,
+		"GROVE_OLD_SHA="+ev.From,
+		"GROVE_NEW_SHA="+ev.To,
+	)
+	output, err := c.CombinedOutput()
+	if len(output) > 0 {
+		m.out().Write(output)
+	}
+	if err != nil {
+		fmt.Fprintf(m.out(), "  Warning: watch command failed for %s/%s: %v\n", ev.Feature, ev.Repo, err)
+	}
+}
+
+// shellCommand builds a command that runs through the user's shell.
+func shellCommand(command string) *exec.Cmd {
+	name, flag := userShell()
+	return exec.Command(name, flag, command)
+}
+
+// userShell returns the user's shell and the flag that makes it run a command
+// string: $SHELL -c on Unix, %COMSPEC% /C on Windows.
+func userShell() (string, string) {
+	if runtime.GOOS == "windows" {
+		if comspec := os.Getenv("COMSPEC"); comspec != "" {
+			return comspec, "/C"
+		}
+		return "cmd", "/C"
+	}
+	if shell := os.Getenv("SHELL"); shell != "" {
+		return shell, "-c"
+	}
+	return "sh", "-c"
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
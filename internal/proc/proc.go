@@ -0,0 +1,43 @@
+// Package proc starts child processes in their own process group, so that a
+// signal sent to Grove can be forwarded to them and everything they spawn.
+package proc
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"time"
+)
+
+// KillGracePeriod is how long a signalled command may take to exit before it is killed.
+const KillGracePeriod = 5 * time.Second
+
+// Interrupted is the cancellation cause used when the user sends a signal.
+// Cancelling a command's context with it forwards the signal to the
+// command's process group instead of killing it outright.
+type Interrupted struct {
+	Signal os.Signal
+}
+
+func (e Interrupted) Error() string {
+	return "interrupted by " + e.Signal.String()
+}
+
+// CommandContext is exec.CommandContext for a command run in its own process
+// group. When ctx is cancelled the group receives the Interrupted signal (or
+// is killed), and after KillGracePeriod the command is killed outright.
+func CommandContext(ctx context.Context, name string, args ...string) *exec.Cmd {
+	c := exec.CommandContext(ctx, name, args...)
+	setGroup(c)
+	c.Cancel = func() error {
+		sig := os.Kill
+		var intr Interrupted
+		if errors.As(context.Cause(ctx), &intr) {
+			sig = intr.Signal
+		}
+		return signalGroup(c.Process, sig)
+	}
+	c.WaitDelay = KillGracePeriod
+	return c
+}
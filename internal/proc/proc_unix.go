@@ -0,0 +1,24 @@
+//go:build !windows
+
+package proc
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// setGroup starts the command in its own process group so signals can
+// be forwarded to it and everything it spawns.
+func setGroup(c *exec.Cmd) {
+	c.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// signalGroup sends sig to the process group led by p.
+func signalGroup(p *os.Process, sig os.Signal) error {
+	s, ok := sig.(syscall.Signal)
+	if !ok {
+		return p.Signal(sig)
+	}
+	return syscall.Kill(-p.Pid, s)
+}
@@ -0,0 +1,21 @@
+//go:build windows
+
+package proc
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// setGroup starts the command in a new process group so console
+// Ctrl-C events are not delivered to it directly.
+func setGroup(c *exec.Cmd) {
+	c.SysProcAttr = &syscall.SysProcAttr{CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP}
+}
+
+// signalGroup terminates the process. Windows cannot deliver Unix
+// signals, so every signal is treated as a kill.
+func signalGroup(p *os.Process, sig os.Signal) error {
+	return p.Kill()
+}
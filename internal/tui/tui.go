@@ -0,0 +1,630 @@
+// Package tui implements `gr ui`, a full-screen browser for sets and features.
+package tui
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vedantprajapati/Grove/internal/git"
+	"github.com/vedantprajapati/Grove/internal/manager"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	refreshInterval = 5 * time.Second
+	maxLogLines     = 1000
+	treeWidth       = 30
+)
+
+var (
+	titleStyle   = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#2ea043"))
+	setStyle     = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#58a6ff"))
+	featureStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#ff7b72"))
+	dimStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("#8b949e"))
+	warnStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("#ff7b72"))
+	okStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("#2ea043"))
+	cursorStyle  = lipgloss.NewStyle().Reverse(true)
+	paneStyle    = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("#30363d"))
+	focusStyle   = paneStyle.BorderForeground(lipgloss.Color("#58a6ff"))
+)
+
+type pane int
+
+const (
+	treePane pane = iota
+	reposPane
+)
+
+// item is a row of the tree: a set header, or a feature when feature is set.
+// Items are a snapshot so the view never reads the config during an action.
+type item struct {
+	set     string
+	feature string
+	path    string
+	tasks   []string
+}
+
+type (
+	statusMsg struct {
+		feature  string
+		statuses []manager.RepoStatus
+		err      error
+	}
+	tickMsg   time.Time
+	logMsg    string
+	actionMsg struct {
+		name string
+		err  error
+	}
+	procMsg struct{ err error }
+)
+
+// model is the bubbletea model behind `gr ui`. Long-running manager calls run
+// in tea.Cmd goroutines; mu keeps them from reading the config while an
+// action that changes it (create, archive, remove) is running.
+type model struct {
+	mgr *manager.Manager
+	mu  *sync.RWMutex
+	log io.Writer
+
+	items      []item
+	cursor     int
+	focus      pane
+	repoCursor int
+
+	statusFor  string
+	statuses   []manager.RepoStatus
+	statusErr  error
+	refreshing bool
+
+	busy   string // Name of the running action, if any
+	cancel context.CancelFunc
+
+	confirm string // Action waiting for y/n
+	prompt  string // Action waiting for text input
+	input   textinput.Model
+
+	logLines []string
+	logView  viewport.Model
+
+	width, height int
+}
+
+// Run starts the UI and blocks until the user quits.
+func Run(mgr *manager.Manager) error {
+	m := &model{
+		mgr:     mgr,
+		mu:      &sync.RWMutex{},
+		input:   textinput.New(),
+		logView: viewport.New(80, 8),
+	}
+	m.buildItems()
+
+	p := tea.NewProgram(m, tea.WithAltScreen())
+
+	// Progress from the manager, git and exec'd commands goes to the log pane.
+	w := &lineWriter{send: func(line string) { p.Send(logMsg(line)) }}
+	m.log = w
+	mgr.Out = w
+	git.Out = w
+
+	_, err := p.Run()
+	return err
+}
+
+func (m *model) Init() tea.Cmd {
+	return tea.Batch(m.refresh(), tick())
+}
+
+func tick() tea.Cmd {
+	return tea.Tick(refreshInterval, func(t time.Time) tea.Msg { return tickMsg(t) })
+}
+
+// buildItems lists sets and their features sorted by name. It must not run
+// while an action is changing the config.
+func (m *model) buildItems() {
+	cfg := m.mgr.Config
+	var sets []string
+	for name := range cfg.Sets {
+		sets = append(sets, name)
+	}
+	sort.Strings(sets)
+
+	selected := m.selected()
+
+	m.items = nil
+	for _, set := range sets {
+		var tasks []string
+		for name := range cfg.Sets[set].Tasks {
+			tasks = append(tasks, name)
+		}
+		sort.Strings(tasks)
+
+		m.items = append(m.items, item{set: set, tasks: tasks})
+		features, _ := m.mgr.SelectFeatures(set)
+		for _, f := range features {
+			m.items = append(m.items, item{set: set, feature: f, path: cfg.Features[f].Path, tasks: tasks})
+		}
+	}
+
+	m.cursor = 0
+	for i, it := range m.items {
+		if it.set == selected.set && it.feature == selected.feature {
+			m.cursor = i
+			return
+		}
+	}
+	// Land on the first feature when the previous selection is gone.
+	for i, it := range m.items {
+		if it.feature != "" {
+			m.cursor = i
+			return
+		}
+	}
+}
+
+func (m *model) selected() item {
+	if m.cursor < len(m.items) {
+		return m.items[m.cursor]
+	}
+	return item{}
+}
+
+// refresh loads the status of the selected feature in the background.
+func (m *model) refresh() tea.Cmd {
+	feature := m.selected().feature
+	if feature == "" || m.refreshing {
+		return nil
+	}
+	m.refreshing = true
+	mgr, mu := m.mgr, m.mu
+	return func() tea.Msg {
+		mu.RLock()
+		defer mu.RUnlock()
+		statuses, err := mgr.GetFeatureStatus(feature)
+		return statusMsg{feature, statuses, err}
+	}
+}
+
+func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.logView.Width = msg.Width - 2
+		m.logView.Height = m.logHeight()
+		return m, nil
+
+	case statusMsg:
+		m.refreshing = false
+		if msg.feature == m.selected().feature {
+			m.statusFor, m.statuses, m.statusErr = msg.feature, msg.statuses, msg.err
+			if m.repoCursor >= len(m.statuses) {
+				m.repoCursor = 0
+			}
+		}
+		return m, nil
+
+	case tickMsg:
+		return m, tea.Batch(m.refresh(), tick())
+
+	case logMsg:
+		m.appendLog(string(msg))
+		return m, nil
+
+	case actionMsg:
+		m.busy, m.cancel = "", nil
+		if msg.err != nil {
+			m.appendLog(warnStyle.Render(fmt.Sprintf("%s failed: %v", msg.name, msg.err)))
+		} else {
+			m.appendLog(okStyle.Render(msg.name + " finished."))
+		}
+		m.buildItems()
+		return m, m.refresh()
+
+	case procMsg:
+		if msg.err != nil {
+			m.appendLog(warnStyle.Render(fmt.Sprintf("command failed: %v", msg.err)))
+		}
+		return m, m.refresh()
+
+	case tea.KeyMsg:
+		switch {
+		case m.prompt != "":
+			return m.updatePrompt(msg)
+		case m.confirm != "":
+			return m.updateConfirm(msg)
+		}
+		return m.updateKeys(msg)
+	}
+	return m, nil
+}
+
+func (m *model) updateKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	sel := m.selected()
+	switch msg.String() {
+	case "q", "ctrl+c":
+		if m.cancel != nil {
+			m.cancel()
+		}
+		return m, tea.Quit
+
+	case "up", "k":
+		m.move(-1)
+		return m, m.refresh()
+	case "down", "j":
+		m.move(1)
+		return m, m.refresh()
+	case "tab":
+		if m.focus == treePane && sel.feature != "" && len(m.statuses) > 0 {
+			m.focus = reposPane
+		} else {
+			m.focus = treePane
+		}
+	case "pgup":
+		m.logView.HalfViewUp()
+	case "pgdown":
+		m.logView.HalfViewDown()
+
+	case "r":
+		return m, m.refresh()
+	case "esc", "c":
+		if m.cancel != nil {
+			m.appendLog(dimStyle.Render("Cancelling " + m.busy + "..."))
+			m.cancel()
+		}
+
+	case "enter", "o":
+		if m.focus == treePane && sel.feature != "" && msg.String() == "enter" && len(m.statuses) > 0 {
+			m.focus = reposPane
+			return m, nil
+		}
+		if dir := m.targetDir(); dir != "" {
+			return m, openIn(dir, shellCommand())
+		}
+	case "e":
+		if dir := m.targetDir(); dir != "" {
+			return m, openIn(dir, editorCommand())
+		}
+
+	case "s":
+		if sel.feature != "" {
+			return m, m.startAction("sync "+sel.feature, false, func(ctx context.Context) error {
+				_, err := m.mgr.SyncFeatureContext(ctx, sel.feature)
+				return err
+			})
+		}
+	case "p":
+		if sel.feature != "" {
+			return m, m.startAction("push "+sel.feature, false, func(ctx context.Context) error {
+				_, err := m.mgr.PushFeatureContext(ctx, sel.feature)
+				return err
+			})
+		}
+	case "t":
+		if sel.feature != "" {
+			tasks := "none defined"
+			if len(sel.tasks) > 0 {
+				tasks = strings.Join(sel.tasks, ", ")
+			}
+			return m, m.startPrompt("task", "Task ("+tasks+"): ")
+		}
+	case "n":
+		if sel.set != "" {
+			return m, m.startPrompt("create", fmt.Sprintf("New feature in %s: ", sel.set))
+		}
+	case "a":
+		if sel.feature != "" && m.busy == "" {
+			m.confirm = "archive"
+		}
+	case "d":
+		if sel.feature != "" && m.busy == "" {
+			m.confirm = "remove"
+		}
+	}
+	return m, nil
+}
+
+func (m *model) updatePrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "ctrl+c":
+		m.prompt = ""
+		return m, nil
+	case "enter":
+		action, value := m.prompt, strings.TrimSpace(m.input.Value())
+		m.prompt = ""
+		if value == "" {
+			return m, nil
+		}
+		sel := m.selected()
+		switch action {
+		case "task":
+			return m, m.startAction("task "+value, false, func(ctx context.Context) error {
+				_, err := m.mgr.RunTask(ctx, sel.feature, value, manager.ExecOptions{Stdout: m.log, Stderr: m.log})
+				return err
+			})
+		case "create":
+			return m, m.startAction("create "+value, true, func(ctx context.Context) error {
+				return m.mgr.CreateFeature(sel.set, value)
+			})
+		}
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+func (m *model) updateConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	action := m.confirm
+	m.confirm = ""
+	if msg.String() != "y" && msg.String() != "Y" {
+		return m, nil
+	}
+
+	feature := m.selected().feature
+	switch action {
+	case "archive":
+		return m, m.startAction("archive "+feature, true, func(ctx context.Context) error {
+			return m.mgr.ArchiveFeature(feature)
+		})
+	case "remove":
+		return m, m.startAction("remove "+feature, true, func(ctx context.Context) error {
+			return m.mgr.RemoveFeature(feature)
+		})
+	}
+	return m, nil
+}
+
+func (m *model) startPrompt(action, prompt string) tea.Cmd {
+	if m.busy != "" {
+		m.appendLog(dimStyle.Render("Wait for " + m.busy + " to finish."))
+		return nil
+	}
+	m.prompt = action
+	m.input.Prompt = prompt
+	m.input.SetValue("")
+	return m.input.Focus()
+}
+
+// startAction runs fn in the background, one action at a time. Actions that
+// change the config hold the write lock so status refreshes wait for them.
+func (m *model) startAction(name string, mutates bool, fn func(ctx context.Context) error) tea.Cmd {
+	if m.busy != "" {
+		m.appendLog(dimStyle.Render("Wait for " + m.busy + " to finish."))
+		return nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	m.busy, m.cancel = name, cancel
+	m.appendLog(titleStyle.Render("▶ " + name))
+
+	mu := m.mu
+	return func() tea.Msg {
+		if mutates {
+			mu.Lock()
+			defer mu.Unlock()
+		} else {
+			mu.RLock()
+			defer mu.RUnlock()
+		}
+		defer cancel()
+		return actionMsg{name, fn(ctx)}
+	}
+}
+
+// targetDir is the selected repo in the repos pane, or the selected feature.
+func (m *model) targetDir() string {
+	sel := m.selected()
+	if sel.feature == "" {
+		return ""
+	}
+	path := sel.path
+	if m.focus == reposPane && m.statusFor == sel.feature && m.repoCursor < len(m.statuses) {
+		return filepath.Join(path, m.statuses[m.repoCursor].Name)
+	}
+	return path
+}
+
+func (m *model) move(delta int) {
+	if m.focus == reposPane {
+		m.repoCursor = clamp(m.repoCursor+delta, len(m.statuses))
+		return
+	}
+	prev := m.selected().feature
+	m.cursor = clamp(m.cursor+delta, len(m.items))
+	if m.selected().feature != prev {
+		m.statuses, m.statusErr, m.repoCursor = nil, nil, 0
+	}
+}
+
+func clamp(i, n int) int {
+	if i < 0 || n == 0 {
+		return 0
+	}
+	if i >= n {
+		return n - 1
+	}
+	return i
+}
+
+func (m *model) appendLog(line string) {
+	m.logLines = append(m.logLines, line)
+	if len(m.logLines) > maxLogLines {
+		m.logLines = m.logLines[len(m.logLines)-maxLogLines:]
+	}
+	m.logView.SetContent(strings.Join(m.logLines, "\n"))
+	m.logView.GotoBottom()
+}
+
+func (m *model) logHeight() int {
+	if h := m.height / 3; h > 4 {
+		return h
+	}
+	return 4
+}
+
+func (m *model) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+
+	title := titleStyle.Render("🌳 Grove")
+	if m.busy != "" {
+		title += dimStyle.Render("  running " + m.busy + "... (c to cancel)")
+	}
+
+	// Title, two borders around the panes, the log pane and the footer.
+	bodyHeight := m.height - m.logHeight() - 6
+	if bodyHeight < 3 {
+		bodyHeight = 3
+	}
+
+	tree, repos := paneStyle, paneStyle
+	if m.focus == treePane {
+		tree = focusStyle
+	} else {
+		repos = focusStyle
+	}
+	body := lipgloss.JoinHorizontal(lipgloss.Top,
+		tree.Width(treeWidth).Height(bodyHeight).Render(m.viewTree(bodyHeight)),
+		repos.Width(m.width-treeWidth-4).Height(bodyHeight).Render(m.viewRepos()),
+	)
+	logPane := paneStyle.Width(m.width - 2).Render(m.logView.View())
+
+	return lipgloss.JoinVertical(lipgloss.Left, title, body, logPane, m.viewFooter())
+}
+
+func (m *model) viewTree(height int) string {
+	if len(m.items) == 0 {
+		return dimStyle.Render("No sets. Use 'gr add set'.")
+	}
+
+	// Scroll so the cursor stays visible.
+	start := 0
+	if m.cursor >= height {
+		start = m.cursor - height + 1
+	}
+	var rows []string
+	for i := start; i < len(m.items) && i < start+height; i++ {
+		it := m.items[i]
+		row := setStyle.Render(it.set)
+		if it.feature != "" {
+			row = "  " + featureStyle.Render(it.feature)
+		}
+		if i == m.cursor {
+			row = cursorStyle.Render(row)
+		}
+		rows = append(rows, row)
+	}
+	return strings.Join(rows, "\n")
+}
+
+func (m *model) viewRepos() string {
+	sel := m.selected()
+	if sel.feature == "" {
+		return dimStyle.Render(fmt.Sprintf("Set %s. Press n to create a feature.", sel.set))
+	}
+	if m.statusFor != sel.feature {
+		return dimStyle.Render("Loading status...")
+	}
+	if m.statusErr != nil {
+		return warnStyle.Render(m.statusErr.Error())
+	}
+
+	rows := []string{lipgloss.NewStyle().Bold(true).Render(fmt.Sprintf("%-18s %-16s %-24s %-12s %s", "REPO", "BRANCH", "STATUS", "SYNC", "BASE"))}
+	for i, s := range m.statuses {
+		changes := okStyle.Render(fmt.Sprintf("%-24s", s.ChangesLabel()))
+		if s.IsDirty || s.Missing || s.Error != "" || s.Operation != "" {
+			changes = warnStyle.Render(fmt.Sprintf("%-24s", s.ChangesLabel()))
+		}
+		name := fmt.Sprintf("%-18s", s.Name)
+		if m.focus == reposPane && i == m.repoCursor {
+			name = cursorStyle.Render(name)
+		}
+		rows = append(rows, fmt.Sprintf("%s %-16s %s %-12s %s", name, s.Branch, changes, s.SyncLabel(), s.BaseLabel()))
+	}
+	return strings.Join(rows, "\n")
+}
+
+func (m *model) viewFooter() string {
+	switch {
+	case m.prompt != "":
+		return m.input.View() + dimStyle.Render("  (enter to confirm, esc to cancel)")
+	case m.confirm != "":
+		verb := "Remove"
+		if m.confirm == "archive" {
+			verb = "Archive"
+		}
+		return warnStyle.Render(fmt.Sprintf("%s feature '%s'? (y/n)", verb, m.selected().feature))
+	}
+	return dimStyle.Render("↑/↓ move • tab repos • s sync • p push • t task • n new • a archive • d remove • o shell • e editor • r refresh • q quit")
+}
+
+// openIn suspends the UI and runs c in dir.
+func openIn(dir string, c *exec.Cmd) tea.Cmd {
+	c.Dir = dir
+	return tea.ExecProcess(c, func(err error) tea.Msg { return procMsg{err} })
+}
+
+func shellCommand() *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		if comspec := os.Getenv("COMSPEC"); comspec != "" {
+			return exec.Command(comspec)
+		}
+		return exec.Command("cmd")
+	}
+	if shell := os.Getenv("SHELL"); shell != "" {
+		return exec.Command(shell)
+	}
+	return exec.Command("sh")
+}
+
+// editorCommand opens $VISUAL or $EDITOR on the current directory.
+func editorCommand() *exec.Cmd {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if editor := os.Getenv(env); editor != "" {
+			fields := strings.Fields(editor)
+			return exec.Command(fields[0], append(fields[1:], ".")...)
+		}
+	}
+	if runtime.GOOS == "windows" {
+		return exec.Command("notepad", ".")
+	}
+	return exec.Command("vi", ".")
+}
+
+// lineWriter forwards complete lines to send.
+type lineWriter struct {
+	mu   sync.Mutex
+	buf  bytes.Buffer
+	send func(line string)
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.buf.Write(p)
+	for {
+		line, err := w.buf.ReadString('\n')
+		if err != nil {
+			// Keep the partial line for the next write.
+			w.buf.Reset()
+			w.buf.WriteString(line)
+			return len(p), nil
+		}
+		w.send(strings.TrimRight(line, "\r\n"))
+	}
+}
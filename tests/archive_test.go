@@ -0,0 +1,59 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/vedantprajapati/Grove/internal/git"
+)
+
+func TestArchiveFeatureKeepsBranches(t *testing.T) {
+	mgr, _ := setupFeature(t, "arch-feat", "svc-a", "svc-b")
+	repoA := filepath.Join(mgr.Config.Features["arch-feat"].Path, "svc-a")
+
+	os.WriteFile(filepath.Join(repoA, "work.txt"), []byte("wip"), 0644)
+	if err := mgr.ArchiveFeature("arch-feat"); err == nil || !strings.Contains(err.Error(), "svc-a") {
+		t.Fatalf("expected archive to refuse a dirty repo, got %v", err)
+	}
+	if _, ok := mgr.Config.Features["arch-feat"]; !ok {
+		t.Fatal("feature should be kept when the archive is refused")
+	}
+
+	execGit(t, repoA, "add", "work.txt")
+	execGit(t, repoA, "-c", "user.email=test@example.com", "-c", "user.name=Test User", "commit", "-m", "Work in progress")
+	head, _ := git.RevParse(repoA, "HEAD")
+
+	if err := mgr.ArchiveFeature("arch-feat"); err != nil {
+		t.Fatalf("ArchiveFeature failed: %v", err)
+	}
+	if _, ok := mgr.Config.Features["arch-feat"]; ok {
+		t.Error("feature should be removed from config")
+	}
+	archived, err := git.RevParse(filepath.Join(mgr.CacheDir, "svc-a"), "archive/arch-feat")
+	if err != nil || archived != head {
+		t.Errorf("expected archive/arch-feat at %s, got %s (%v)", head, archived, err)
+	}
+
+	// Archiving a feature of the same name again keeps the earlier archive.
+	if err := mgr.CreateFeature("test-set", "arch-feat"); err != nil {
+		t.Fatalf("CreateFeature failed: %v", err)
+	}
+	os.WriteFile(filepath.Join(repoA, "more.txt"), []byte("more"), 0644)
+	execGit(t, repoA, "add", "more.txt")
+	execGit(t, repoA, "-c", "user.email=test@example.com", "-c", "user.name=Test User", "commit", "-m", "More work")
+	second, _ := git.RevParse(repoA, "HEAD")
+
+	if err := mgr.ArchiveFeature("arch-feat"); err != nil {
+		t.Fatalf("second ArchiveFeature failed: %v", err)
+	}
+	bare := filepath.Join(mgr.CacheDir, "svc-a")
+	if archived, _ := git.RevParse(bare, "archive/arch-feat"); archived != head {
+		t.Errorf("the first archive was overwritten: archive/arch-feat is at %s, want %s", archived, head)
+	}
+	branches, _ := git.RunGit(bare, "for-each-ref", "--format=%(refname:short) %(objectname)", "refs/heads/archive/arch-feat-*")
+	if len(strings.Split(branches, "\n")) != 1 || !strings.HasSuffix(branches, " "+second) {
+		t.Errorf("expected one new archive branch at %s, got %q", second, branches)
+	}
+}
@@ -0,0 +1,61 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/vedantprajapati/Grove/internal/git"
+	"github.com/vedantprajapati/Grove/internal/manager"
+)
+
+func TestStatusAgainstBaseBranch(t *testing.T) {
+	mgr, remotesDir := setupFeature(t, "base-feat", "svc-a", "svc-b")
+	feat := mgr.Config.Features["base-feat"]
+	if feat.Bases["svc-a"] != "main" || feat.Bases["svc-b"] != "main" {
+		t.Fatalf("expected bases to be recorded, got %v", feat.Bases)
+	}
+
+	identity := []string{"-c", "user.email=test@example.com", "-c", "user.name=Test User"}
+
+	// svc-a: one unpushed commit on the feature branch.
+	repoA := filepath.Join(feat.Path, "svc-a")
+	os.WriteFile(filepath.Join(repoA, "feature.txt"), []byte("feature"), 0644)
+	execGit(t, repoA, "add", "feature.txt")
+	execGit(t, repoA, append(identity, "commit", "-m", "Feature work")...)
+
+	// svc-b: main moves on upstream while the feature has no commits.
+	remoteB := filepath.Join(remotesDir, "svc-b")
+	os.WriteFile(filepath.Join(remoteB, "main.txt"), []byte("main"), 0644)
+	execGit(t, remoteB, "add", "main.txt")
+	execGit(t, remoteB, "commit", "-m", "Main moves on")
+	if err := git.FetchBare(filepath.Join(mgr.CacheDir, "svc-b")); err != nil {
+		t.Fatal(err)
+	}
+
+	statuses, err := mgr.GetFeatureStatus("base-feat")
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, b := statuses[0], statuses[1]
+	if a.HasUpstream || a.Base != "main" || a.BaseAhead != 1 || a.BaseBehind != 0 || !a.Changed {
+		t.Errorf("svc-a: expected 1 commit beyond main without an upstream, got %+v", a)
+	}
+	if b.BaseAhead != 0 || b.BaseBehind != 1 || b.Changed {
+		t.Errorf("svc-b: expected unchanged and 1 behind main, got %+v", b)
+	}
+	if got := b.BaseLabel(); got != "main +0 -1 unchanged" {
+		t.Errorf("svc-b: unexpected base label %q", got)
+	}
+
+	// --changed selects the same repos status marks changed, even though
+	// svc-b's HEAD no longer matches main.
+	mgr.Filter = manager.RepoFilter{Changed: true}
+	statuses, err = mgr.GetFeatureStatus("base-feat")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statuses) != 1 || statuses[0].Name != "svc-a" {
+		t.Errorf("expected --changed to select only svc-a, got %+v", statuses)
+	}
+}
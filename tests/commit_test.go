@@ -0,0 +1,101 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/vedantprajapati/Grove/internal/git"
+	"github.com/vedantprajapati/Grove/internal/manager"
+)
+
+func TestCommitFeatureAcrossDirtyRepos(t *testing.T) {
+	mgr, _ := setupFeature(t, "commit-feat", "svc-a", "svc-b", "svc-c")
+	featPath := mgr.Config.Features["commit-feat"].Path
+	for _, repo := range []string{"svc-a", "svc-b", "svc-c"} {
+		dir := filepath.Join(featPath, repo)
+		execGit(t, dir, "config", "user.email", "test@example.com")
+		execGit(t, dir, "config", "user.name", "Test User")
+	}
+
+	// svc-a: tracked change; svc-b: only an untracked file; svc-c: clean.
+	os.WriteFile(filepath.Join(featPath, "svc-a", "README.md"), []byte("# Changed\n"), 0644)
+	os.WriteFile(filepath.Join(featPath, "svc-b", "notes.txt"), []byte("notes\n"), 0644)
+
+	results, err := mgr.CommitFeature("commit-feat", manager.CommitOptions{Message: "Rename field", All: true, Trailer: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results[0].SHA == "" {
+		t.Errorf("expected svc-a to be committed, got %+v", results[0])
+	}
+	if results[1].Skipped != "nothing staged" {
+		t.Errorf("expected -a to leave svc-b's untracked file alone, got %+v", results[1])
+	}
+	if results[2].Skipped != "clean" {
+		t.Errorf("expected svc-c to be skipped as clean, got %+v", results[2])
+	}
+
+	msg, _ := git.RunGit(filepath.Join(featPath, "svc-a"), "log", "-1", "--format=%B")
+	if !strings.HasPrefix(msg, "Rename field") || !strings.Contains(msg, "Grove-Feature: commit-feat") {
+		t.Errorf("expected message with feature trailer, got %q", msg)
+	}
+
+	// Pathspecs stage untracked files; declined repos are not committed.
+	os.WriteFile(filepath.Join(featPath, "svc-a", "notes.txt"), []byte("notes\n"), 0644)
+	var asked []string
+	results, err = mgr.CommitFeature("commit-feat", manager.CommitOptions{
+		Message:   "Add notes",
+		Pathspecs: []string{"notes.txt"},
+		Confirm: func(repo, diff string) bool {
+			asked = append(asked, repo)
+			if !strings.Contains(diff, "b/"+repo+"/notes.txt") {
+				t.Errorf("expected repo-prefixed diff for %s, got:\n%s", repo, diff)
+			}
+			return repo == "svc-b"
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(asked, ",") != "svc-a,svc-b" {
+		t.Errorf("expected to be asked for svc-a then svc-b, got %v", asked)
+	}
+	if results[0].Skipped != "declined" || results[1].SHA == "" {
+		t.Errorf("expected svc-a declined and svc-b committed, got %+v", results)
+	}
+	if staged, _ := git.RunGit(filepath.Join(featPath, "svc-a"), "diff", "--cached", "--name-only"); staged != "" {
+		t.Errorf("expected the declined repo's index to be restored, still staged: %q", staged)
+	}
+
+	// A pathspec missing from a repo does not stop the others being staged
+	// there, and what the user staged beforehand survives a decline.
+	os.WriteFile(filepath.Join(featPath, "svc-b", "only-b.txt"), []byte("b\n"), 0644)
+	os.WriteFile(filepath.Join(featPath, "svc-c", "README.md"), []byte("# Staged by hand\n"), 0644)
+	os.WriteFile(filepath.Join(featPath, "svc-c", "only-b.txt"), []byte("c\n"), 0644)
+	execGit(t, filepath.Join(featPath, "svc-c"), "add", "README.md")
+	results, err = mgr.CommitFeature("commit-feat", manager.CommitOptions{
+		Message:   "Add more notes",
+		Pathspecs: []string{"notes.txt", "only-b.txt"},
+		Confirm:   func(repo, diff string) bool { return repo != "svc-c" },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results[0].SHA == "" || results[1].SHA == "" || results[2].Skipped != "declined" {
+		t.Fatalf("expected svc-a and svc-b committed and svc-c declined, got %+v", results)
+	}
+	files, _ := git.RunGit(filepath.Join(featPath, "svc-a"), "show", "--name-only", "--format=", "HEAD")
+	if files != "notes.txt" {
+		t.Errorf("expected svc-a to commit notes.txt, got %q", files)
+	}
+	staged, _ := git.RunGit(filepath.Join(featPath, "svc-c"), "diff", "--cached", "--name-only")
+	if staged != "README.md" {
+		t.Errorf("expected svc-c to keep only its own staged README.md, got %q", staged)
+	}
+
+	if _, err := mgr.CommitFeature("commit-feat", manager.CommitOptions{}); err == nil {
+		t.Error("expected an error without a message")
+	}
+}
@@ -0,0 +1,449 @@
+package tests
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/vedantprajapati/Grove/internal/config"
+	"github.com/vedantprajapati/Grove/internal/manager"
+)
+
+func TestConcurrentConfigUpdates(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), ".groverc")
+
+	// Each manager loads the config before any of them saves, like parallel
+	// gr processes; every change must survive.
+	const n = 10
+	mgrs := make([]*manager.Manager, n)
+	for i := range mgrs {
+		mgr, err := manager.NewManagerWithConfig(configFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		mgrs[i] = mgr
+	}
+
+	var wg sync.WaitGroup
+	for i, mgr := range mgrs {
+		wg.Add(1)
+		go func(i int, mgr *manager.Manager) {
+			defer wg.Done()
+			if err := mgr.AddSet(fmt.Sprintf("set-%d", i), []string{"https://example.com/repo.git"}); err != nil {
+				t.Error(err)
+			}
+		}(i, mgr)
+	}
+	wg.Wait()
+
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Sets) != n {
+		t.Errorf("expected %d sets, got %d", n, len(cfg.Sets))
+	}
+
+	// A failed update writes nothing; a successful one refreshes the caller.
+	if err := cfg.Update(func(c *config.Config) error { return fmt.Errorf("nope") }); err == nil {
+		t.Error("expected the update error to be returned")
+	}
+	mgrs[0].AddSet("late", nil)
+	if _, ok := mgrs[0].Config.Sets["set-9"]; !ok {
+		t.Error("expected Update to refresh the in-memory config with other processes' changes")
+	}
+
+	matches, _ := filepath.Glob(configFile + ".tmp-*")
+	if len(matches) != 0 {
+		t.Errorf("expected no temp files left behind, got %v", matches)
+	}
+}
+
+func TestConfigRecoversFromBackup(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), ".groverc")
+
+	cfg, _ := config.LoadConfig(configFile)
+	cfg.Sets["first"] = config.Set{Repos: []string{"a"}}
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+	cfg.Sets["second"] = config.Set{Repos: []string{"b"}}
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Simulate a crash that left truncated JSON.
+	data, _ := os.ReadFile(configFile)
+	os.WriteFile(configFile, data[:len(data)/2], 0644)
+
+	recovered, err := config.LoadConfig(configFile)
+	if err != nil {
+		t.Fatalf("expected recovery from backup, got %v", err)
+	}
+	if _, ok := recovered.Sets["first"]; !ok || len(recovered.Sets) != 1 {
+		t.Errorf("expected the previous version from the backup, got %v", recovered.Sets)
+	}
+	if damaged, err := os.ReadFile(configFile + ".corrupt"); err != nil || !strings.HasPrefix(string(data), string(damaged)) {
+		t.Errorf("expected the damaged file to be kept aside: %v", err)
+	}
+	if _, err := config.LoadConfig(configFile); err != nil {
+		t.Errorf("expected the restored file to load cleanly: %v", err)
+	}
+
+	// Without any backup, the parse error is reported.
+	bare := filepath.Join(t.TempDir(), ".groverc")
+	os.WriteFile(bare, []byte("{"), 0644)
+	if _, err := config.LoadConfig(bare); err == nil {
+		t.Error("expected an error for a damaged config without backups")
+	}
+}
+
+func TestConfigMigration(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), ".groverc")
+	original := `{"root_dir": "/tmp/grove", "sets": {"web": {"repos": ["a"], "skills_dir": ""}}, "features": {}}`
+	os.WriteFile(configFile, []byte(original), 0644)
+
+	plan, err := config.PlanMigration(configFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.From != 0 || plan.To != config.CurrentVersion || len(plan.Steps) != config.CurrentVersion {
+		t.Errorf("unexpected plan: %+v", plan)
+	}
+	if data, _ := os.ReadFile(configFile); string(data) != original {
+		t.Error("planning a migration must not change the file")
+	}
+
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Version != config.CurrentVersion || len(cfg.Sets["web"].Repos) != 1 {
+		t.Errorf("expected the migrated config to keep its sets, got %+v", cfg)
+	}
+	if data, _ := os.ReadFile(configFile + ".v0.bak"); string(data) != original {
+		t.Errorf("expected the original to be kept as .v0.bak, got %q", data)
+	}
+	if data, _ := os.ReadFile(configFile); !strings.Contains(string(data), `"version": 1`) {
+		t.Errorf("expected the file to be saved at the current version:\n%s", data)
+	}
+	if plan, _ = config.PlanMigration(configFile); plan.From != plan.To {
+		t.Errorf("expected no further migrations, got %+v", plan)
+	}
+}
+
+func TestConfigRejectsUnknownFieldsAndNewerVersions(t *testing.T) {
+	dir := t.TempDir()
+	cases := map[string]string{
+		"top-level": `{"version": 1, "root_dir": "/tmp", "colour": "blue"}`,
+		"nested":    `{"version": 1, "sets": {"web": {"repo": ["a"]}}}`,
+		"newer":     `{"version": 999, "root_dir": "/tmp"}`,
+	}
+	for name, content := range cases {
+		configFile := filepath.Join(dir, name)
+		os.WriteFile(configFile, []byte(content), 0644)
+		if _, err := config.LoadConfig(configFile); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+		if data, _ := os.ReadFile(configFile); string(data) != content {
+			t.Errorf("%s: the rejected file must be left alone", name)
+		}
+	}
+}
+
+func TestYAMLAndTOMLConfigs(t *testing.T) {
+	dir := t.TempDir()
+
+	yamlFile := filepath.Join(dir, ".groverc.yaml")
+	os.WriteFile(yamlFile, []byte(`version: 1
+root_dir: /tmp/grove
+# Services owned by the web team
+sets:
+  web:
+    repos:
+      - https://example.com/api.git # the public API
+    skills_dir: ""
+features: {}
+`), 0644)
+
+	cfg, err := config.LoadConfig(yamlFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Format() != config.FormatYAML || cfg.Sets["web"].Repos[0] != "https://example.com/api.git" {
+		t.Fatalf("unexpected YAML config: %+v", cfg)
+	}
+	if err := cfg.Update(func(c *config.Config) error {
+		c.Sets["docs"] = config.Set{Repos: []string{"https://example.com/docs.git"}}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(yamlFile)
+	for _, want := range []string{"# Services owned by the web team", "# the public API", "docs:"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("expected %q in the saved YAML:\n%s", want, data)
+		}
+	}
+
+	// Converting keeps the content and moves the old file aside.
+	stale, err := config.LoadConfig(yamlFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tomlFile, err := cfg.Convert(config.FormatTOML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tomlFile != filepath.Join(dir, ".groverc.toml") {
+		t.Errorf("unexpected TOML path %s", tomlFile)
+	}
+	if _, err := os.Stat(yamlFile + ".converted"); err != nil {
+		t.Errorf("expected the YAML file to be kept aside: %v", err)
+	}
+	cfg, err = config.LoadConfig(tomlFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Format() != config.FormatTOML || len(cfg.Sets) != 2 || cfg.Version != config.CurrentVersion {
+		t.Errorf("unexpected TOML config: %+v", cfg)
+	}
+
+	// A process that loaded the old file must not recreate it.
+	if err := stale.Update(func(c *config.Config) error {
+		c.Sets["stale"] = config.Set{}
+		return nil
+	}); err == nil {
+		t.Error("expected updating a converted config to fail")
+	}
+	if _, err := os.Stat(yamlFile); !os.IsNotExist(err) {
+		t.Errorf("the converted YAML file was recreated: %v", err)
+	}
+
+	// .groverc keeps its name when it becomes JSON.
+	rcFile := filepath.Join(t.TempDir(), ".groverc")
+	os.WriteFile(rcFile, []byte("root_dir: /tmp/grove\n"), 0644)
+	rc, err := config.LoadConfig(rcFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	before, _ := os.ReadFile(rcFile)
+	if path, err := rc.Convert(config.FormatJSON); err != nil || path != rcFile {
+		t.Errorf("expected .groverc converted in place, got %s, %v", path, err)
+	}
+	if backup, err := os.ReadFile(rcFile + ".bak.1"); err != nil || string(backup) != string(before) {
+		t.Errorf("expected the YAML .groverc backed up before converting in place, got %q (%v)", backup, err)
+	}
+
+	// There is nothing to convert without a file.
+	missing, err := config.LoadConfig(filepath.Join(t.TempDir(), ".groverc.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := missing.Convert(config.FormatTOML); err == nil {
+		t.Error("expected converting a missing config to fail")
+	}
+	if _, err := os.Stat(config.PathForFormat(missing.Path(), config.FormatTOML)); !os.IsNotExist(err) {
+		t.Errorf("converting a missing config wrote a file: %v", err)
+	}
+
+	// Without an extension, the format is sniffed from the content.
+	sniffed := filepath.Join(dir, "sniffed")
+	os.WriteFile(sniffed, []byte("# comment\nroot_dir = \"/tmp/grove\"\n"), 0644)
+	if cfg, err = config.LoadConfig(sniffed); err != nil || cfg.Format() != config.FormatTOML || cfg.RootDir != "/tmp/grove" {
+		t.Errorf("expected a sniffed TOML config, got %+v, %v", cfg, err)
+	}
+}
+
+func TestProjectConfigLayering(t *testing.T) {
+	dir := t.TempDir()
+	globalFile := filepath.Join(dir, ".groverc")
+	os.WriteFile(globalFile, []byte(`{"version": 1, "root_dir": "/global/root", "sets": {
+		"api": {"repos": ["https://example.com/api.git"], "skills_dir": "",
+		        "tasks": {"test": {"command": "go test ./..."}}}}, "features": {}}`), 0644)
+
+	project := filepath.Join(dir, "workspace")
+	sub := filepath.Join(project, "services", "web")
+	os.MkdirAll(sub, 0755)
+	os.WriteFile(filepath.Join(project, ".grove.yaml"), []byte(`root_dir: ./worktrees
+sets:
+  web:
+    repos: [https://example.com/web.git]
+  api:
+    tasks:
+      lint: {command: golangci-lint run}
+`), 0644)
+
+	cfg, err := config.LoadConfig(globalFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path, err := cfg.ApplyProject(sub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != filepath.Join(project, ".grove.yaml") || cfg.ProjectFile() != path {
+		t.Errorf("expected the project file to be found from a subdirectory, got %q", path)
+	}
+	if cfg.RootDir != filepath.Join(project, "worktrees") {
+		t.Errorf("expected root_dir relative to the project file, got %s", cfg.RootDir)
+	}
+	if _, ok := cfg.Sets["web"]; !ok {
+		t.Error("expected the project's set to be added")
+	}
+	if tasks := cfg.Sets["api"].Tasks; len(tasks) != 2 || len(cfg.Sets["api"].Repos) != 1 {
+		t.Errorf("expected tasks merged into the global set, got %+v", cfg.Sets["api"])
+	}
+
+	// Saving keeps the project layer out of the global file.
+	if err := cfg.Update(func(c *config.Config) error {
+		c.Features["web-feat"] = config.Feature{Path: "/tmp/web-feat", Set: "web"}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cfg.Sets["web"]; !ok {
+		t.Error("expected the project layer to survive an update")
+	}
+	global, _ := config.LoadConfig(globalFile)
+	if _, ok := global.Sets["web"]; ok || global.RootDir != "/global/root" || len(global.Sets["api"].Tasks) != 1 {
+		t.Errorf("expected only global settings in the global file, got %+v", global)
+	}
+	if _, ok := global.Features["web-feat"]; !ok {
+		t.Error("expected the feature to be saved globally")
+	}
+
+	// Edits to a set the project touched keep only the global parts.
+	if err := cfg.Update(func(c *config.Config) error {
+		api := c.Sets["api"]
+		api.SkillsDir = "/skills/api"
+		api.Repos = append(api.Repos, "https://example.com/api-docs.git")
+		c.Sets["api"] = api
+		return nil
+	}); err != nil {
+		t.Fatalf("editing the global parts of a project set failed: %v", err)
+	}
+	global, _ = config.LoadConfig(globalFile)
+	api := global.Sets["api"]
+	if _, ok := api.Tasks["lint"]; ok || len(api.Tasks) != 1 || api.SkillsDir != "/skills/api" || len(api.Repos) != 2 {
+		t.Errorf("expected the edit saved without the project's task, got %+v", api)
+	}
+	if _, ok := global.Sets["web"]; ok {
+		t.Error("the project's set leaked into the global file")
+	}
+
+	// Values the project contributes are edited in the project file.
+	for what, edit := range map[string]func(c *config.Config){
+		"web skills_dir": func(c *config.Config) {
+			web := c.Sets["web"]
+			web.SkillsDir = "/skills/web"
+			c.Sets["web"] = web
+		},
+		"api lint task": func(c *config.Config) {
+			c.Sets["api"].Tasks["lint"] = config.Task{Command: "make lint"}
+		},
+		"web removal": func(c *config.Config) { delete(c.Sets, "web") },
+		"root_dir":    func(c *config.Config) { c.RootDir = "/elsewhere" },
+	} {
+		err := cfg.Update(func(c *config.Config) error {
+			edit(c)
+			return nil
+		})
+		if err == nil || !strings.Contains(err.Error(), ".grove.yaml") {
+			t.Errorf("%s: expected the edit to point at the project file, got %v", what, err)
+		}
+	}
+	global, _ = config.LoadConfig(globalFile)
+	if _, ok := global.Sets["web"]; ok || global.RootDir != "/global/root" {
+		t.Errorf("a rejected edit reached the global file: %+v", global)
+	}
+
+	os.WriteFile(filepath.Join(sub, ".grove.json"), []byte(`{"features": {}}`), 0644)
+	if _, err := cfg.ApplyProject(sub); err == nil {
+		t.Error("expected features in a project file to be rejected")
+	}
+}
+
+func TestDefaultPaths(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("XDG_DATA_HOME", "")
+	t.Setenv("XDG_CACHE_HOME", "")
+	t.Setenv(config.EnvHome, "")
+	t.Setenv(config.EnvConfig, "")
+	t.Setenv(config.EnvCacheDir, "")
+
+	if runtime.GOOS == "linux" {
+		paths, err := config.DefaultPaths()
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := config.Paths{
+			Config: filepath.Join(home, ".config", "grove", "config"),
+			Data:   filepath.Join(home, ".local", "share", "grove"),
+			Cache:  filepath.Join(home, ".cache", "grove"),
+		}
+		if paths != want {
+			t.Errorf("expected XDG defaults %+v, got %+v", want, paths)
+		}
+	}
+
+	// Existing ~/.groverc and ~/.grove keep being used.
+	os.WriteFile(filepath.Join(home, ".groverc.yaml"), []byte("root_dir: /tmp\n"), 0644)
+	os.MkdirAll(filepath.Join(home, ".grove"), 0755)
+	paths, _ := config.DefaultPaths()
+	if paths.Config != filepath.Join(home, ".groverc.yaml") || paths.Cache != filepath.Join(home, ".grove", "cache") {
+		t.Errorf("expected the existing files to be used, got %+v", paths)
+	}
+
+	groveHome := t.TempDir()
+	t.Setenv(config.EnvHome, groveHome)
+	t.Setenv(config.EnvCacheDir, "/shared/cache")
+	paths, _ = config.DefaultPaths()
+	if paths.Config != filepath.Join(groveHome, "config") || paths.Data != groveHome || paths.Cache != "/shared/cache" {
+		t.Errorf("expected GROVE_HOME and GROVE_CACHE_DIR to apply, got %+v", paths)
+	}
+	t.Setenv(config.EnvConfig, "/etc/grove.yaml")
+	if paths, _ = config.DefaultPaths(); paths.Config != "/etc/grove.yaml" {
+		t.Errorf("expected GROVE_CONFIG to win, got %s", paths.Config)
+	}
+
+	// Features go under GROVE_HOME even though $HOME is set.
+	cfg, err := config.LoadConfig(filepath.Join(groveHome, "config"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.RootDir != filepath.Join(groveHome, "worktrees") {
+		t.Errorf("expected the default root_dir in GROVE_HOME, got %s", cfg.RootDir)
+	}
+}
+
+func TestManagerPathOverrides(t *testing.T) {
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "grove.json")
+	t.Setenv(config.EnvRootDir, filepath.Join(dir, "env-root"))
+
+	mgr, err := manager.NewManagerWithOptions(manager.Options{ConfigPath: configFile, CacheDir: filepath.Join(dir, "cache")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mgr.Root() != filepath.Join(dir, "env-root") || mgr.CacheDir != filepath.Join(dir, "cache") {
+		t.Errorf("expected GROVE_ROOT_DIR and the cache option to apply, got %s %s", mgr.Root(), mgr.CacheDir)
+	}
+
+	mgr, _ = manager.NewManagerWithOptions(manager.Options{ConfigPath: configFile, RootDir: filepath.Join(dir, "flag-root")})
+	if mgr.Root() != filepath.Join(dir, "flag-root") {
+		t.Errorf("expected the root option to win over the environment, got %s", mgr.Root())
+	}
+	if err := mgr.AddSet("web", nil); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(configFile); strings.Contains(string(data), "flag-root") {
+		t.Errorf("the root override must not be saved:\n%s", data)
+	}
+}
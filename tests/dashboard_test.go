@@ -0,0 +1,56 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/vedantprajapati/Grove/internal/manager"
+)
+
+func TestFeatureDashboardSummaries(t *testing.T) {
+	mgr, _ := setupFeature(t, "dash-feat", "svc-a", "svc-b")
+	featPath := mgr.Config.Features["dash-feat"].Path
+	os.WriteFile(filepath.Join(featPath, "svc-b", "notes.txt"), []byte("wip"), 0644)
+
+	statuses, err := mgr.GetFeatureStatus("dash-feat")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := mgr.SummarizeFeature("dash-feat", statuses)
+	if sum.Set != "test-set" || sum.Repos != 2 || sum.Dirty != 1 || sum.Conflicted != 0 {
+		t.Errorf("unexpected summary: %+v", sum)
+	}
+	if time.Since(sum.LastActivity) > time.Hour {
+		t.Errorf("expected recent activity, got %v", sum.LastActivity)
+	}
+
+	// A stopped rebase without conflicted files is not a conflict.
+	stopped := mgr.SummarizeFeature("dash-feat", []manager.RepoStatus{
+		{Name: "svc-a", Operation: "rebase"},
+		{Name: "svc-b", Conflicted: 2, Operation: "merge"},
+	})
+	if stopped.Conflicted != 1 || stopped.InProgress != 2 {
+		t.Errorf("expected 1 conflicted and 2 stopped repos, got %+v", stopped)
+	}
+
+	old := manager.FeatureSummary{Feature: "old-feat", Set: "test-set", LastActivity: time.Now().Add(-30 * 24 * time.Hour)}
+	other := manager.FeatureSummary{Feature: "a-feat", Set: "other-set"}
+	sums := []manager.FeatureSummary{other, sum, old}
+
+	if err := manager.SortSummaries(sums, manager.SortByActivity); err != nil {
+		t.Fatal(err)
+	}
+	if sums[0].Set != "other-set" || sums[1].Feature != "dash-feat" || sums[2].Feature != "old-feat" {
+		t.Errorf("expected sets in order and recent first, got %s, %s, %s", sums[0].Feature, sums[1].Feature, sums[2].Feature)
+	}
+	if err := manager.SortSummaries(sums, "bogus"); err == nil {
+		t.Error("expected an error for an unknown sort order")
+	}
+
+	stale := manager.StaleSummaries(sums, 14*24*time.Hour)
+	if len(stale) != 2 || stale[0].Feature != "a-feat" || stale[1].Feature != "old-feat" {
+		t.Errorf("expected old-feat and a-feat (no commits) to be stale, got %+v", stale)
+	}
+}
@@ -0,0 +1,75 @@
+package tests
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/vedantprajapati/Grove/internal/manager"
+)
+
+func TestDiffFeatureAcrossRepos(t *testing.T) {
+	mgr, _ := setupFeature(t, "diff-feat", "svc-a", "svc-b")
+	featPath := mgr.Config.Features["diff-feat"].Path
+
+	// svc-a: a committed change; svc-b: an uncommitted one.
+	repoA := filepath.Join(featPath, "svc-a")
+	os.WriteFile(filepath.Join(repoA, "api.go"), []byte("package api\n"), 0644)
+	execGit(t, repoA, "add", "api.go")
+	execGit(t, repoA, "-c", "user.email=test@example.com", "-c", "user.name=Test User", "commit", "-m", "Add api")
+	os.WriteFile(filepath.Join(featPath, "svc-b", "README.md"), []byte("# Changed\n"), 0644)
+
+	diffs, err := mgr.DiffFeature("diff-feat", manager.DiffOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diffs[0].Files) != 0 || len(diffs[1].Files) != 1 || diffs[1].Files[0].Path != "svc-b/README.md" {
+		t.Errorf("expected only svc-b's uncommitted change, got %+v", diffs)
+	}
+
+	diffs, err = mgr.DiffFeature("diff-feat", manager.DiffOptions{Base: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diffs[0].Files) != 1 || diffs[0].Files[0].Path != "svc-a/api.go" || diffs[0].Files[0].Added != 1 {
+		t.Errorf("expected svc-a's committed file against base, got %+v", diffs[0])
+	}
+	if !strings.Contains(diffs[0].Patch, "+++ b/svc-a/api.go") {
+		t.Errorf("expected repo-prefixed paths in patch:\n%s", diffs[0].Patch)
+	}
+
+	// The combined patch applies from the feature directory.
+	patch := filepath.Join(t.TempDir(), "feature.patch")
+	os.WriteFile(patch, []byte(diffs[0].Patch+diffs[1].Patch), 0644)
+	check := exec.Command("git", "apply", "-R", "--check", "-p1", patch)
+	check.Dir = featPath
+	if out, err := check.CombinedOutput(); err != nil {
+		t.Errorf("combined patch does not apply from the feature root: %v\n%s", err, out)
+	}
+}
+
+func TestDiffSplitsRenames(t *testing.T) {
+	mgr, _ := setupFeature(t, "rename-feat", "svc-a")
+	featPath := mgr.Config.Features["rename-feat"].Path
+	repoA := filepath.Join(featPath, "svc-a")
+
+	execGit(t, repoA, "mv", "README.md", "GUIDE.md")
+	diffs, err := mgr.DiffFeature("rename-feat", manager.DiffOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := diffs[0].Files
+	if len(files) != 2 || files[0].Path != "svc-a/GUIDE.md" || files[1].Path != "svc-a/README.md" {
+		t.Fatalf("expected the rename as an add and a delete, got %+v", files)
+	}
+
+	patch := filepath.Join(t.TempDir(), "rename.patch")
+	os.WriteFile(patch, []byte(diffs[0].Patch), 0644)
+	check := exec.Command("git", "apply", "-R", "--check", "-p1", patch)
+	check.Dir = featPath
+	if out, err := check.CombinedOutput(); err != nil {
+		t.Errorf("rename patch does not apply from the feature root: %v\n%s", err, out)
+	}
+}
@@ -0,0 +1,303 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vedantprajapati/Grove/internal/manager"
+)
+
+func TestExecPrefixesAndGroupsOutput(t *testing.T) {
+	mgr, _ := setupFeature(t, "exec-feat", "svc-a", "svc-b")
+
+	var stdout, stderr bytes.Buffer
+	_, err := mgr.ExecFeatureWithOptions(context.Background(), "exec-feat", "git", []string{"rev-parse", "--abbrev-ref", "HEAD"}, manager.ExecOptions{
+		Stdout: &stdout,
+		Stderr: &stderr,
+	})
+	if err != nil {
+		t.Fatalf("exec failed: %v", err)
+	}
+	for _, want := range []string{"[svc-a] exec-feat\n", "[svc-b] exec-feat\n"} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Errorf("expected %q in streamed output:\n%s", want, stdout.String())
+		}
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	_, err = mgr.ExecFeatureWithOptions(context.Background(), "exec-feat", "git", []string{"rev-parse", "--verify", "no-such-ref"}, manager.ExecOptions{
+		Group:      true,
+		ExitPolicy: manager.ExitPolicyNever,
+		Stdout:     &stdout,
+		Stderr:     &stderr,
+	})
+	if err != nil {
+		t.Fatalf("exit policy 'never' should not fail: %v", err)
+	}
+	if !strings.Contains(stdout.String(), "--- [svc-a] ---") {
+		t.Errorf("expected grouped header in output:\n%s", stdout.String())
+	}
+	if !strings.Contains(stderr.String(), "Needed a single revision") {
+		t.Errorf("expected git error on stderr, got:\n%s", stderr.String())
+	}
+}
+
+func TestExecReportsFailures(t *testing.T) {
+	mgr, _ := setupFeature(t, "fail-feat", "svc-a", "svc-b")
+
+	// Fails only in svc-a, where the README is removed first.
+	os.Remove(filepath.Join(mgr.Config.Features["fail-feat"].Path, "svc-a", "README.md"))
+
+	var out bytes.Buffer
+	results, err := mgr.ExecFeatureWithOptions(context.Background(), "fail-feat", "git", []string{"diff", "--quiet"}, manager.ExecOptions{
+		Stdout: &out,
+		Stderr: &out,
+	})
+	var execErr *manager.ExecError
+	if !errors.As(err, &execErr) {
+		t.Fatalf("expected *ExecError, got %v", err)
+	}
+	if execErr.Code != 1 || len(execErr.Failed) != 1 || execErr.Failed[0].Repo != "svc-a" {
+		t.Errorf("unexpected failure report: %+v", execErr)
+	}
+	if len(results) != 2 || results[0].Repo != "svc-a" || results[1].ExitCode != 0 {
+		t.Errorf("results not in set order: %+v", results)
+	}
+
+	if _, err := mgr.ExecFeatureWithOptions(context.Background(), "fail-feat", "git", []string{"diff", "--quiet"}, manager.ExecOptions{
+		ExitPolicy: manager.ExitPolicyAll,
+		Stdout:     &out,
+		Stderr:     &out,
+	}); err != nil {
+		t.Errorf("exit policy 'all' should pass when one repo succeeds: %v", err)
+	}
+
+	if err := mgr.ExecFeature("fail-feat", "no-such-command-grove", nil); err == nil {
+		t.Error("ExecFeature should fail when the command cannot run")
+	}
+}
+
+func TestExecFollowsSetOrder(t *testing.T) {
+	mgr, _ := setupFeature(t, "order-feat", "svc-c", "svc-a", "svc-b")
+
+	for _, tc := range []struct {
+		name string
+		jobs int
+		opts manager.ExecOptions
+	}{
+		{"serial", 1, manager.ExecOptions{}},
+		{"grouped", 0, manager.ExecOptions{Group: true}},
+	} {
+		var out bytes.Buffer
+		mgr.Jobs = tc.jobs
+		tc.opts.Stdout = &out
+		tc.opts.Stderr = &out
+		if _, err := mgr.ExecFeatureWithOptions(context.Background(), "order-feat", "git", []string{"rev-parse", "--abbrev-ref", "HEAD"}, tc.opts); err != nil {
+			t.Fatalf("%s: exec failed: %v", tc.name, err)
+		}
+
+		c := strings.Index(out.String(), "[svc-c]")
+		a := strings.Index(out.String(), "[svc-a]")
+		b := strings.Index(out.String(), "[svc-b]")
+		if !(c < a && a < b) {
+			t.Errorf("%s: output not in set order:\n%s", tc.name, out.String())
+		}
+	}
+}
+
+func TestExecShellAndTemplates(t *testing.T) {
+	mgr, _ := setupFeature(t, "tmpl-feat", "svc-a")
+
+	var out bytes.Buffer
+	opts := manager.ExecOptions{Shell: true, Stdout: &out, Stderr: &out}
+	if _, err := mgr.ExecFeatureWithOptions(context.Background(), "tmpl-feat", `echo "{{.Repo}}:{{.Branch}}:{{.Base}}" | tr a-z A-Z && echo "$GROVE_FEATURE/$GROVE_SET"`, nil, opts); err != nil {
+		t.Fatalf("exec failed: %v\n%s", err, out.String())
+	}
+	for _, want := range []string{"[svc-a] SVC-A:TMPL-FEAT:MAIN\n", "[svc-a] tmpl-feat/test-set\n"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected %q in output:\n%s", want, out.String())
+		}
+	}
+
+	if _, err := mgr.ExecFeatureWithOptions(context.Background(), "tmpl-feat", "echo", []string{"{{.Nope}}"}, opts); err == nil {
+		t.Error("expected an error for an unknown template field")
+	}
+	if _, err := mgr.ExecFeatureWithOptions(context.Background(), "tmpl-feat", "echo", []string{"{{.Repo"}, opts); err == nil {
+		t.Error("expected an error for a malformed template")
+	}
+
+	out.Reset()
+	literal := manager.ExecOptions{NoTemplate: true, Stdout: &out, Stderr: &out}
+	if _, err := mgr.ExecFeatureWithOptions(context.Background(), "tmpl-feat", "echo", []string{"{{.Names}}", "{{json .}}"}, literal); err != nil {
+		t.Fatalf("--no-template should pass templates through: %v", err)
+	}
+	if !strings.Contains(out.String(), "[svc-a] {{.Names}} {{json .}}\n") {
+		t.Errorf("expected the literal arguments in output:\n%s", out.String())
+	}
+
+	out.Reset()
+	opts.Shell = false
+	if _, err := mgr.ExecFeatureWithOptions(context.Background(), "tmpl-feat", "echo", []string{`{{"{{"}}.Names}} {{.Repo}}`}, opts); err != nil {
+		t.Fatalf("exec with an escaped template failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "[svc-a] {{.Names}} svc-a\n") {
+		t.Errorf("expected the escape to produce a literal {{:\n%s", out.String())
+	}
+}
+
+func TestExecAcrossFeatures(t *testing.T) {
+	mgr, _ := setupFeature(t, "multi-b", "svc-a", "svc-b")
+	if err := mgr.CreateFeature("test-set", "multi-a"); err != nil {
+		t.Fatalf("CreateFeature failed: %v", err)
+	}
+
+	names, err := mgr.SelectFeatures("test-set")
+	if err != nil {
+		t.Fatalf("SelectFeatures failed: %v", err)
+	}
+	if len(names) != 2 || names[0] != "multi-a" || names[1] != "multi-b" {
+		t.Fatalf("unexpected features: %v", names)
+	}
+	if _, err := mgr.SelectFeatures("no-such-set"); err == nil {
+		t.Error("expected an error for an unknown set")
+	}
+
+	var out bytes.Buffer
+	results, err := mgr.ExecFeatures(context.Background(), names, "git", []string{"rev-parse", "--abbrev-ref", "HEAD"}, manager.ExecOptions{
+		Stdout: &out,
+		Stderr: &out,
+	})
+	if err != nil {
+		t.Fatalf("ExecFeatures failed: %v", err)
+	}
+	if len(results) != 4 || results[0].Feature != "multi-a" || results[3].Feature != "multi-b" || results[3].Repo != "svc-b" {
+		t.Errorf("results not grouped by feature then repo: %+v", results)
+	}
+	if strings.Index(out.String(), "=== multi-a ===") > strings.Index(out.String(), "=== multi-b ===") {
+		t.Errorf("features not in name order:\n%s", out.String())
+	}
+}
+
+func TestExecTimeoutAndCancel(t *testing.T) {
+	mgr, _ := setupFeature(t, "slow-feat", "svc-a")
+
+	var out bytes.Buffer
+	start := time.Now()
+	results, err := mgr.ExecFeatureWithOptions(context.Background(), "slow-feat", "sleep 10", nil, manager.ExecOptions{
+		Shell:   true,
+		Timeout: 200 * time.Millisecond,
+		Stdout:  &out,
+		Stderr:  &out,
+	})
+	if err == nil {
+		t.Error("a timed out repo should fail the run")
+	}
+	if len(results) != 1 || !results[0].TimedOut {
+		t.Errorf("expected svc-a to be marked timed out: %+v", results)
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Errorf("timeout did not kill the command (took %s)", time.Since(start))
+	}
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	time.AfterFunc(200*time.Millisecond, func() { cancel(manager.Interrupted{Signal: os.Interrupt}) })
+	results, _ = mgr.ExecFeatureWithOptions(ctx, "slow-feat", "sleep 10", nil, manager.ExecOptions{
+		Shell:  true,
+		Stdout: &out,
+		Stderr: &out,
+	})
+	if len(results) != 1 || !results[0].Cancelled {
+		t.Errorf("expected svc-a to be marked cancelled: %+v", results)
+	}
+}
+
+func TestExecFailFast(t *testing.T) {
+	mgr, _ := setupFeature(t, "fast-feat", "svc-a", "svc-b", "svc-c")
+
+	for _, jobs := range []int{1, 3} {
+		var out bytes.Buffer
+		mgr.Jobs = jobs
+		start := time.Now()
+		results, err := mgr.ExecFeatureWithOptions(context.Background(), "fast-feat", `if [ "$GROVE_REPO" = svc-a ]; then exit 3; fi; sleep 10`, nil, manager.ExecOptions{
+			Shell:    true,
+			FailFast: true,
+			Stdout:   &out,
+			Stderr:   &out,
+		})
+		if time.Since(start) > 5*time.Second {
+			t.Errorf("jobs=%d: --fail-fast did not stop the other repos (took %s)", jobs, time.Since(start))
+		}
+
+		var execErr *manager.ExecError
+		if !errors.As(err, &execErr) || execErr.Code != 3 || len(execErr.Failed) != 3 {
+			t.Fatalf("jobs=%d: expected every repo reported with exit code 3, got %v", jobs, err)
+		}
+		if len(results) != 3 || results[0].ExitCode != 3 || results[0].Cancelled {
+			t.Fatalf("jobs=%d: expected svc-a to fail on its own: %+v", jobs, results)
+		}
+		for _, r := range results[1:] {
+			if !r.Cancelled {
+				t.Errorf("jobs=%d: expected %s to be cancelled: %+v", jobs, r.Repo, r)
+			}
+		}
+	}
+}
+
+func TestExecFailFastKeepsOwnFailures(t *testing.T) {
+	mgr, _ := setupFeature(t, "own-feat", "svc-a", "svc-b", "svc-c")
+	mgr.Jobs = 3
+
+	// svc-b exits with 4 straight away, but a leftover child holds its output
+	// open until after svc-a has failed and cancelled the run.
+	var out bytes.Buffer
+	results, _ := mgr.ExecFeatureWithOptions(context.Background(), "own-feat", `case "$GROVE_REPO" in
+svc-a) sleep 0.3; exit 3;;
+svc-b) (sleep 1 &); exit 4;;
+*) sleep 10;;
+esac`, nil, manager.ExecOptions{
+		Shell:    true,
+		FailFast: true,
+		Stdout:   &out,
+		Stderr:   &out,
+	})
+
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %+v", results)
+	}
+	if r := results[1]; r.Cancelled || r.ExitCode != 4 {
+		t.Errorf("expected svc-b to keep its own failure: %+v", r)
+	}
+	if r := results[2]; !r.Cancelled || r.ExitCode != -1 {
+		t.Errorf("expected svc-c to be cancelled: %+v", r)
+	}
+}
+
+func TestSyncForwardsInterrupt(t *testing.T) {
+	mgr, _ := setupFeature(t, "intr-feat", "svc-a")
+	repo := filepath.Join(mgr.Config.Features["intr-feat"].Path, "svc-a")
+
+	// A slow upload-pack that records the signal it receives.
+	marker := filepath.Join(t.TempDir(), "signal.txt")
+	execGit(t, repo, "config", "remote.origin.uploadpack",
+		`trap 'echo INT > "`+filepath.ToSlash(marker)+`"; kill $!; exit 1' INT; sleep 10 & wait; true`)
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	time.AfterFunc(500*time.Millisecond, func() { cancel(manager.Interrupted{Signal: os.Interrupt}) })
+	start := time.Now()
+	if _, err := mgr.SyncFeatureContext(ctx, "intr-feat"); err == nil {
+		t.Error("an interrupted sync should fail")
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Errorf("sync was not stopped (took %s)", time.Since(start))
+	}
+	if data, err := os.ReadFile(marker); err != nil || strings.TrimSpace(string(data)) != "INT" {
+		t.Errorf("expected git's children to receive SIGINT, got %q (%v)", data, err)
+	}
+}
@@ -0,0 +1,83 @@
+package tests
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/vedantprajapati/Grove/internal/manager"
+)
+
+func TestRepoFilters(t *testing.T) {
+	mgr, remotesDir := setupFeature(t, "filter-feat", "svc-a", "svc-b", "docs")
+	featPath := mgr.Config.Features["filter-feat"].Path
+
+	// svc-a is dirty, svc-b has a commit beyond base.
+	os.WriteFile(filepath.Join(featPath, "svc-a", "NEW.md"), []byte("new"), 0644)
+	svcB := filepath.Join(featPath, "svc-b")
+	execGit(t, svcB, "config", "user.email", "test@example.com")
+	execGit(t, svcB, "config", "user.name", "Test User")
+	execGit(t, svcB, "commit", "--allow-empty", "-m", "Feature work")
+
+	for _, tc := range []struct {
+		name   string
+		filter manager.RepoFilter
+		want   []string
+	}{
+		{"none", manager.RepoFilter{}, []string{"svc-a", "svc-b", "docs"}},
+		{"glob", manager.RepoFilter{Only: []string{"svc-*"}}, []string{"svc-a", "svc-b"}},
+		{"exclude", manager.RepoFilter{Only: []string{"svc-*"}, Exclude: []string{"svc-b"}}, []string{"svc-a"}},
+		{"dirty", manager.RepoFilter{Dirty: true}, []string{"svc-a"}},
+		{"changed", manager.RepoFilter{Changed: true}, []string{"svc-b"}},
+	} {
+		mgr.Filter = tc.filter
+		statuses, err := mgr.GetFeatureStatus("filter-feat")
+		if err != nil {
+			t.Fatalf("%s: GetFeatureStatus failed: %v", tc.name, err)
+		}
+		var got []string
+		for _, s := range statuses {
+			got = append(got, s.Name)
+		}
+		if len(got) != len(tc.want) {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.want, got)
+			continue
+		}
+		for i := range got {
+			if got[i] != tc.want[i] {
+				t.Errorf("%s: expected %v, got %v", tc.name, tc.want, got)
+				break
+			}
+		}
+	}
+
+	mgr.Filter = manager.RepoFilter{Only: []string{"["}}
+	if _, err := mgr.GetFeatureStatus("filter-feat"); err == nil {
+		t.Error("expected an error for an invalid pattern")
+	}
+
+	// Push only the changed repo, once a cancelled push has left it alone.
+	mgr.Filter = manager.RepoFilter{Changed: true}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := mgr.PushFeatureContext(ctx, "filter-feat"); err == nil {
+		t.Error("a cancelled push should fail")
+	}
+	check := exec.Command("git", "rev-parse", "--verify", "filter-feat")
+	check.Dir = filepath.Join(remotesDir, "svc-b")
+	if err := check.Run(); err == nil {
+		t.Error("svc-b should not have been pushed by the cancelled push")
+	}
+
+	if err := mgr.PushFeature("filter-feat"); err != nil {
+		t.Fatalf("PushFeature failed: %v", err)
+	}
+	execGit(t, filepath.Join(remotesDir, "svc-b"), "rev-parse", "--verify", "filter-feat")
+	check = exec.Command("git", "rev-parse", "--verify", "filter-feat")
+	check.Dir = filepath.Join(remotesDir, "svc-a")
+	if err := check.Run(); err == nil {
+		t.Error("svc-a should not have been pushed")
+	}
+}
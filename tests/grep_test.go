@@ -0,0 +1,52 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/vedantprajapati/Grove/internal/manager"
+)
+
+func TestGrepFeatureAcrossRepos(t *testing.T) {
+	mgr, _ := setupFeature(t, "grep-feat", "svc-a", "svc-b")
+	featPath := mgr.Config.Features["grep-feat"].Path
+
+	repoB := filepath.Join(featPath, "svc-b")
+	os.WriteFile(filepath.Join(repoB, "client.go"), []byte("package client\n\nfunc GetUser() {}\nvar x = GetUserID\n"), 0644)
+	execGit(t, repoB, "add", "client.go")
+
+	files, failed, err := mgr.GrepFeature("grep-feat", "test", manager.GrepOptions{IgnoreCase: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(failed) != 0 {
+		t.Fatalf("unexpected failures: %v", failed)
+	}
+	if len(files) != 2 || files[0].Path != "svc-a/README.md" || files[1].Path != "svc-b/README.md" {
+		t.Fatalf("expected both READMEs in set order, got %+v", files)
+	}
+	if m := files[0].Matches; len(m) != 1 || m[0].Line != 1 || m[0].Text != "# Test svc-a" {
+		t.Errorf("unexpected match: %+v", m)
+	}
+
+	files, _, _ = mgr.GrepFeature("grep-feat", "GetUser", manager.GrepOptions{WordRegexp: true})
+	if len(files) != 1 || files[0].Path != "svc-b/client.go" || files[0].Count != 1 || files[0].Matches[0].Line != 3 {
+		t.Errorf("expected one whole-word match in svc-b/client.go, got %+v", files)
+	}
+
+	files, _, _ = mgr.GrepFeature("grep-feat", "GetUser", manager.GrepOptions{Count: true})
+	if len(files) != 1 || files[0].Count != 2 || len(files[0].Matches) != 0 {
+		t.Errorf("expected a count of 2 without lines, got %+v", files)
+	}
+
+	files, _, _ = mgr.GrepFeature("grep-feat", "Test", manager.GrepOptions{Pathspecs: []string{"*.go"}})
+	if len(files) != 0 {
+		t.Errorf("expected the pathspec to exclude READMEs, got %+v", files)
+	}
+
+	files, failed, err = mgr.GrepFeature("grep-feat", "no-such-text", manager.GrepOptions{})
+	if err != nil || len(failed) != 0 || len(files) != 0 {
+		t.Errorf("expected no matches and no errors, got %+v %v %v", files, failed, err)
+	}
+}
@@ -231,3 +231,47 @@ func execGit(t *testing.T, dir string, args ...string) {
 		t.Fatalf("Git %v in %s failed: %v\n%s", args, dir, err, out)
 	}
 }
+
+// setupFeature creates local remotes for repoNames, a set containing them and a
+// feature checked out from that set, all isolated under a temp directory.
+func setupFeature(t *testing.T, featureName string, repoNames ...string) (*manager.Manager, string) {
+	t.Helper()
+	tempDir := t.TempDir()
+	remotesDir := filepath.Join(tempDir, "remotes")
+
+	var repoURLs []string
+	for _, name := range repoNames {
+		path := filepath.Join(remotesDir, name)
+		if err := os.MkdirAll(path, 0755); err != nil {
+			t.Fatal(err)
+		}
+		execGit(t, path, "init")
+		execGit(t, path, "config", "user.email", "test@example.com")
+		execGit(t, path, "config", "user.name", "Test User")
+		execGit(t, path, "branch", "-m", "main")
+		os.WriteFile(filepath.Join(path, "README.md"), []byte("# Test "+name), 0644)
+		execGit(t, path, "add", ".")
+		execGit(t, path, "commit", "-m", "Initial commit")
+		repoURLs = append(repoURLs, path)
+	}
+
+	mgr, err := manager.NewManagerWithConfig(filepath.Join(tempDir, ".groverc"))
+	if err != nil {
+		t.Fatalf("Failed to init manager: %v", err)
+	}
+	mgr.Config.RootDir = filepath.Join(tempDir, "grove")
+	mgr.CacheDir = filepath.Join(tempDir, "cache")
+	mgr.DataDir = filepath.Join(tempDir, "data")
+	// Config updates reload the file, so the root dir must be saved first.
+	if err := mgr.SaveConfig(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := mgr.AddSet("test-set", repoURLs); err != nil {
+		t.Fatalf("AddSet failed: %v", err)
+	}
+	if err := mgr.CreateFeature("test-set", featureName); err != nil {
+		t.Fatalf("CreateFeature failed: %v", err)
+	}
+	return mgr, remotesDir
+}
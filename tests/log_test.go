@@ -0,0 +1,67 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/vedantprajapati/Grove/internal/manager"
+)
+
+func TestLogFeatureMergesReposByTime(t *testing.T) {
+	mgr, _ := setupFeature(t, "log-feat", "svc-a", "svc-b")
+	featPath := mgr.Config.Features["log-feat"].Path
+
+	commit := func(repo, file, msg, author, date string) {
+		// Ordering uses the committer date, so pin both dates.
+		t.Setenv("GIT_COMMITTER_DATE", date)
+		dir := filepath.Join(featPath, repo)
+		os.WriteFile(filepath.Join(dir, file), []byte(msg+"\n"), 0644)
+		execGit(t, dir, "add", file)
+		execGit(t, dir, "-c", "user.email=test@example.com", "-c", "user.name="+author,
+			"commit", "-m", msg, "--date", date)
+	}
+	commit("svc-a", "a1.txt", "first in a", "Alice", "2024-01-01T10:00:00Z")
+	commit("svc-b", "b1.txt", "second in b", "Bob", "2024-01-02T10:00:00Z")
+	commit("svc-a", "a2.txt", "third in a", "Alice", "2024-01-03T10:00:00Z")
+
+	entries, failed, err := mgr.LogFeature("log-feat", manager.LogOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(failed) != 0 {
+		t.Fatalf("unexpected failures: %v", failed)
+	}
+	want := []struct{ repo, subject string }{
+		{"svc-a", "third in a"},
+		{"svc-b", "second in b"},
+		{"svc-a", "first in a"},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("expected %d commits since base, got %+v", len(want), entries)
+	}
+	for i, w := range want {
+		if entries[i].Repo != w.repo || entries[i].Subject != w.subject {
+			t.Errorf("entry %d: expected %s %q, got %s %q", i, w.repo, w.subject, entries[i].Repo, entries[i].Subject)
+		}
+	}
+
+	entries, _, _ = mgr.LogFeature("log-feat", manager.LogOptions{Author: "Bob"})
+	if len(entries) != 1 || entries[0].Repo != "svc-b" {
+		t.Errorf("expected only Bob's commit, got %+v", entries)
+	}
+	entries, _, _ = mgr.LogFeature("log-feat", manager.LogOptions{Grep: "third"})
+	if len(entries) != 1 || entries[0].Subject != "third in a" {
+		t.Errorf("expected only the grep match, got %+v", entries)
+	}
+	entries, _, _ = mgr.LogFeature("log-feat", manager.LogOptions{Limit: 2})
+	if len(entries) != 2 {
+		t.Errorf("expected the limit to apply overall, got %d entries", len(entries))
+	}
+
+	// --full also includes the initial commits the repos share with base.
+	entries, _, _ = mgr.LogFeature("log-feat", manager.LogOptions{Full: true})
+	if len(entries) != 5 {
+		t.Errorf("expected 5 commits with full history, got %d", len(entries))
+	}
+}
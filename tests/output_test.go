@@ -0,0 +1,53 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/vedantprajapati/Grove/internal/manager"
+)
+
+func TestResultsMarshalForScripts(t *testing.T) {
+	mgr, _ := setupFeature(t, "json-feat", "svc-a", "svc-b")
+	var progress bytes.Buffer
+	mgr.Out = &progress
+
+	results, err := mgr.SyncFeatureContext(context.Background(), "json-feat")
+	if err != nil {
+		t.Fatalf("sync failed: %v", err)
+	}
+	if len(results) != 2 || !results[0].OK || results[0].Repo != "svc-a" || results[1].Repo != "svc-b" {
+		t.Errorf("unexpected sync results: %+v", results)
+	}
+	if !strings.Contains(progress.String(), "svc-a synced.") {
+		t.Errorf("expected progress on Manager.Out, got:\n%s", progress.String())
+	}
+
+	statuses, err := mgr.GetFeatureStatus("json-feat")
+	if err != nil {
+		t.Fatalf("status failed: %v", err)
+	}
+	data, _ := json.Marshal(statuses[0])
+	for _, want := range []string{`"name":"svc-a"`, `"ahead":0`, `"behind":0`, `"dirty":false`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("expected %s in %s", want, data)
+		}
+	}
+
+	var out bytes.Buffer
+	execResults, _ := mgr.ExecFeatureWithOptions(context.Background(), "json-feat", "git", []string{"rev-parse", "--verify", "no-such-ref"}, manager.ExecOptions{
+		Stdout: &out,
+		Stderr: &out,
+	})
+	data, _ = json.Marshal(execResults[0])
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded["exit_code"] != float64(128) || decoded["error"] == nil || decoded["repo"] != "svc-a" {
+		t.Errorf("unexpected exec result JSON: %s", data)
+	}
+}
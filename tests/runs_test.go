@@ -0,0 +1,103 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/vedantprajapati/Grove/internal/manager"
+)
+
+func TestExecRunsAreRecordedAndRetried(t *testing.T) {
+	mgr, _ := setupFeature(t, "runs-feat", "svc-a", "svc-b")
+
+	// Fails only in svc-a, where the README is removed first.
+	readme := filepath.Join(mgr.Config.Features["runs-feat"].Path, "svc-a", "README.md")
+	os.Remove(readme)
+
+	var out bytes.Buffer
+	mgr.ExecFeatureWithOptions(context.Background(), "runs-feat", "git", []string{"diff", "--stat"}, manager.ExecOptions{
+		ExitPolicy: manager.ExitPolicyNever,
+		Record:     true,
+		Stdout:     &out,
+		Stderr:     &out,
+	})
+	mgr.ExecFeatureWithOptions(context.Background(), "runs-feat", "git", []string{"diff", "--quiet"}, manager.ExecOptions{
+		Record: true,
+		Stdout: &out,
+		Stderr: &out,
+	})
+
+	runs, err := mgr.ListRuns()
+	if err != nil || len(runs) != 2 {
+		t.Fatalf("expected 2 saved runs, got %d (%v)", len(runs), err)
+	}
+	run := runs[0]
+	if run.CommandLine() != "git diff --quiet" {
+		t.Errorf("expected most recent run first, got %q", run.CommandLine())
+	}
+	failed := run.Failed()
+	if len(failed) != 1 || failed[0].Repo != "svc-a" || failed[0].ExitCode != 1 {
+		t.Fatalf("unexpected failed repos: %+v", failed)
+	}
+
+	stat, err := mgr.RunLog(runs[1], runs[1].Results[0])
+	if err != nil || !strings.Contains(stat, "README.md") {
+		t.Errorf("expected diff output in svc-a log, got %q (%v)", stat, err)
+	}
+
+	// Restore the file so the retry succeeds, and check only svc-a reran.
+	if err := os.WriteFile(readme, []byte("# Test svc-a"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	results, err := mgr.RetryFailed(context.Background(), run.ID, manager.ExecOptions{
+		Record: true,
+		Stdout: &out,
+		Stderr: &out,
+	})
+	if err != nil {
+		t.Fatalf("retry failed: %v\n%s", err, out.String())
+	}
+	if len(results) != 1 || results[0].Repo != "svc-a" {
+		t.Errorf("expected retry in svc-a only, got %+v", results)
+	}
+	if !mgr.Filter.IsZero() {
+		t.Errorf("retry should restore the repo filter, got %s", mgr.Filter.String())
+	}
+
+	if _, err := mgr.RetryFailed(context.Background(), runs[1].ID, manager.ExecOptions{Stdout: &out}); err == nil {
+		t.Error("expected an error retrying a run with no failures")
+	}
+	if _, err := mgr.LoadRun("../state.json"); err == nil {
+		t.Error("expected invalid run id to be rejected")
+	}
+}
+
+func TestExecRunsWithoutRecording(t *testing.T) {
+	mgr, _ := setupFeature(t, "norec-feat", "svc-a")
+
+	// A file where the runs directory belongs makes recording impossible.
+	if err := os.MkdirAll(mgr.DataDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(mgr.DataDir, "runs"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	mgr.Out = &out
+	results, err := mgr.ExecFeatureWithOptions(context.Background(), "norec-feat", "git", []string{"status"}, manager.ExecOptions{
+		Record: true,
+		Stdout: &out,
+		Stderr: &out,
+	})
+	if err != nil || len(results) != 1 || results[0].Err != nil {
+		t.Fatalf("expected the command to run unrecorded, got %+v (%v)\n%s", results, err, out.String())
+	}
+	if !strings.Contains(out.String(), "will not be saved") {
+		t.Errorf("expected a warning about the unsaved run, got:\n%s", out.String())
+	}
+}
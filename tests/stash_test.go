@@ -0,0 +1,121 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestStashFeatureGroups(t *testing.T) {
+	mgr, _ := setupFeature(t, "stash-feat", "svc-a", "svc-b", "svc-c")
+	featPath := mgr.Config.Features["stash-feat"].Path
+	readmeA := filepath.Join(featPath, "svc-a", "README.md")
+	readmeB := filepath.Join(featPath, "svc-b", "README.md")
+	for _, repo := range []string{"svc-a", "svc-b", "svc-c"} {
+		dir := filepath.Join(featPath, repo)
+		execGit(t, dir, "config", "user.email", "test@example.com")
+		execGit(t, dir, "config", "user.name", "Test User")
+	}
+
+	os.WriteFile(readmeA, []byte("# A work\n"), 0644)
+	os.WriteFile(readmeB, []byte("# B work\n"), 0644)
+	os.WriteFile(filepath.Join(featPath, "svc-c", "new.txt"), []byte("untracked\n"), 0644)
+
+	id, results, err := mgr.StashPush("stash-feat", "halfway", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results[0].Skipped != "" || results[1].Skipped != "" || results[2].Skipped != "nothing to stash" {
+		t.Errorf("expected svc-a and svc-b stashed and svc-c left alone, got %+v", results)
+	}
+	if data, _ := os.ReadFile(readmeA); string(data) != "# Test svc-a" {
+		t.Errorf("expected svc-a to be clean after push, got %q", data)
+	}
+
+	groups, err := mgr.StashList("stash-feat")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(groups) != 1 || groups[0].ID != id || groups[0].Message != "halfway" || strings.Join(groups[0].Repos, ",") != "svc-a,svc-b" {
+		t.Fatalf("expected one group for svc-a and svc-b, got %+v", groups)
+	}
+
+	// A conflicting change in svc-b: svc-a is still restored, svc-b keeps its stash.
+	os.WriteFile(readmeB, []byte("# Conflicting\n"), 0644)
+	execGit(t, filepath.Join(featPath, "svc-b"), "commit", "-am", "Conflicting change")
+
+	results, err = mgr.StashPop("stash-feat", "")
+	if err == nil {
+		t.Error("expected pop to report the conflict")
+	}
+	if results[0].Error != "" || results[0].Conflicted {
+		t.Errorf("expected svc-a to be restored, got %+v", results[0])
+	}
+	if !results[1].Conflicted {
+		t.Errorf("expected svc-b to conflict, got %+v", results[1])
+	}
+	if results[2].Skipped != "no stash" {
+		t.Errorf("expected svc-c to have no stash, got %+v", results[2])
+	}
+	if data, _ := os.ReadFile(readmeA); string(data) != "# A work\n" {
+		t.Errorf("expected svc-a's change back, got %q", data)
+	}
+
+	groups, _ = mgr.StashList("stash-feat")
+	if len(groups) != 1 || strings.Join(groups[0].Repos, ",") != "svc-b" {
+		t.Errorf("expected only svc-b to keep the stash, got %+v", groups)
+	}
+	execGit(t, filepath.Join(featPath, "svc-b"), "reset", "--hard")
+
+	if _, err := mgr.StashDrop("stash-feat", id); err != nil {
+		t.Fatal(err)
+	}
+	if groups, _ = mgr.StashList("stash-feat"); len(groups) != 0 {
+		t.Errorf("expected no groups after drop, got %+v", groups)
+	}
+	if _, err := mgr.StashPop("stash-feat", ""); err == nil {
+		t.Error("expected an error popping with no stashes")
+	}
+}
+
+func TestStashIDsAreUnique(t *testing.T) {
+	mgr, _ := setupFeature(t, "ids-feat", "svc-a")
+	repo := filepath.Join(mgr.Config.Features["ids-feat"].Path, "svc-a")
+	execGit(t, repo, "config", "user.email", "test@example.com")
+	execGit(t, repo, "config", "user.name", "Test User")
+	readme := filepath.Join(repo, "README.md")
+
+	// Two pushes in quick succession, usually within the same second.
+	var ids []string
+	for _, content := range []string{"first\n", "second\n"} {
+		os.WriteFile(readme, []byte(content), 0644)
+		id, _, err := mgr.StashPush("ids-feat", "", false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, id)
+	}
+	if ids[0] == ids[1] {
+		t.Fatalf("expected distinct ids, got %s twice", ids[0])
+	}
+
+	// A stash whose message only mentions the label is not part of the group.
+	os.WriteFile(readme, []byte("manual\n"), 0644)
+	execGit(t, repo, "stash", "push", "-m", "see grove:ids-feat:"+ids[1])
+
+	groups, err := mgr.StashList("ids-feat")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(groups) != 2 || groups[0].ID != ids[1] || groups[1].ID != ids[0] {
+		t.Fatalf("expected both groups newest first, got %+v", groups)
+	}
+
+	if _, err := mgr.StashPop("ids-feat", ids[0]); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(readme); string(data) != "first\n" {
+		t.Errorf("expected the first group to be popped, got %q", data)
+	}
+}
@@ -0,0 +1,109 @@
+package tests
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestDetailedRepoStatus(t *testing.T) {
+	mgr, _ := setupFeature(t, "status-feat", "svc-a", "svc-b", "svc-c")
+	featPath := mgr.Config.Features["status-feat"].Path
+	identity := []string{"-c", "user.email=test@example.com", "-c", "user.name=Test User"}
+	commit := func(dir, content, msg string) {
+		os.WriteFile(filepath.Join(dir, "README.md"), []byte(content), 0644)
+		execGit(t, dir, "add", "README.md")
+		execGit(t, dir, append(identity, "commit", "-m", msg)...)
+	}
+
+	// svc-a: a merge stopped on a conflict.
+	repoA := filepath.Join(featPath, "svc-a")
+	execGit(t, repoA, "switch", "-c", "side")
+	commit(repoA, "side", "Side change")
+	execGit(t, repoA, "switch", "status-feat")
+	commit(repoA, "ours", "Our change")
+	merge := exec.Command("git", append(identity, "merge", "side")...)
+	merge.Dir = repoA
+	if err := merge.Run(); err == nil {
+		t.Fatal("expected merge conflict")
+	}
+
+	// svc-b: one stash, one staged and one untracked file.
+	repoB := filepath.Join(featPath, "svc-b")
+	os.WriteFile(filepath.Join(repoB, "README.md"), []byte("stashed"), 0644)
+	execGit(t, repoB, append(identity, "stash")...)
+	os.WriteFile(filepath.Join(repoB, "staged.txt"), []byte("staged"), 0644)
+	execGit(t, repoB, "add", "staged.txt")
+	os.WriteFile(filepath.Join(repoB, "untracked.txt"), []byte("new"), 0644)
+
+	// svc-c: worktree deleted out from under Grove.
+	os.RemoveAll(filepath.Join(featPath, "svc-c"))
+
+	statuses, err := mgr.GetFeatureStatus("status-feat")
+	if err != nil {
+		t.Fatalf("GetFeatureStatus failed: %v", err)
+	}
+
+	a, b, c := statuses[0], statuses[1], statuses[2]
+	if a.Conflicted != 1 || a.Operation != "merge" || !a.IsDirty {
+		t.Errorf("svc-a: expected a conflicted merge, got %+v", a)
+	}
+	if a.LastCommit == nil || a.LastCommit.Subject != "Our change" || a.LastCommit.Author != "Test User" {
+		t.Errorf("svc-a: unexpected last commit %+v", a.LastCommit)
+	}
+	if b.Stashes != 1 || b.Staged != 1 || b.Untracked != 1 || b.Unstaged != 0 || b.Operation != "" {
+		t.Errorf("svc-b: unexpected counts %+v", b)
+	}
+	if b.Branch != "status-feat" || b.Detached {
+		t.Errorf("svc-b: unexpected branch %q (detached %v)", b.Branch, b.Detached)
+	}
+	if !c.Missing {
+		t.Errorf("svc-c: expected missing worktree, got %+v", c)
+	}
+
+	execGit(t, repoB, "switch", "--detach")
+	statuses, _ = mgr.GetFeatureStatus("status-feat")
+	if !statuses[1].Detached || statuses[1].Branch != "HEAD" {
+		t.Errorf("svc-b: expected detached HEAD, got %+v", statuses[1])
+	}
+}
+
+func TestStatusWithoutShowStash(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the git stand-in is a shell script")
+	}
+	mgr, _ := setupFeature(t, "oldgit-feat", "svc-a")
+	repo := filepath.Join(mgr.Config.Features["oldgit-feat"].Path, "svc-a")
+	os.WriteFile(filepath.Join(repo, "README.md"), []byte("stashed"), 0644)
+	execGit(t, repo, "-c", "user.email=test@example.com", "-c", "user.name=Test User", "stash")
+
+	// Stand in for git before 2.35, which rejects --show-stash in porcelain v2.
+	realGit, err := exec.LookPath("git")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bin := t.TempDir()
+	script := `#!/bin/sh
+for arg in "$@"; do
+	if [ "$arg" = --show-stash ]; then
+		echo "fatal: --show-stash is not supported with --porcelain=v2" >&2
+		exit 128
+	fi
+done
+exec "` + realGit + `" "$@"
+`
+	if err := os.WriteFile(filepath.Join(bin, "git"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	statuses, err := mgr.GetFeatureStatus("oldgit-feat")
+	if err != nil {
+		t.Fatalf("GetFeatureStatus failed: %v", err)
+	}
+	if len(statuses) != 1 || statuses[0].Stashes != 1 || statuses[0].Error != "" {
+		t.Errorf("expected one stash counted without --show-stash, got %+v", statuses)
+	}
+}
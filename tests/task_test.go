@@ -0,0 +1,74 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/vedantprajapati/Grove/internal/config"
+	"github.com/vedantprajapati/Grove/internal/manager"
+)
+
+func TestRunTaskDependencies(t *testing.T) {
+	mgr, _ := setupFeature(t, "task-feat", "svc-a", "shared", "svc-b")
+	set := mgr.Config.Sets["test-set"]
+	set.Tasks = map[string]config.Task{
+		"build": {
+			Repos: map[string]string{
+				"shared": "sleep 0.2 && echo {{.Repo}} >> ../order.txt",
+				"svc-a":  "echo {{.Repo}} >> ../order.txt",
+			},
+			DependsOn: map[string][]string{"svc-a": {"shared"}},
+		},
+		"broken": {
+			Command:   "exit 3",
+			DependsOn: map[string][]string{"svc-a": {"shared"}},
+		},
+		"cyclic": {
+			Command:   "true",
+			DependsOn: map[string][]string{"svc-a": {"shared"}, "shared": {"svc-a"}},
+		},
+	}
+	mgr.Config.Sets["test-set"] = set
+
+	var out bytes.Buffer
+	opts := manager.ExecOptions{Stdout: &out, Stderr: &out}
+	report, err := mgr.RunTask(context.Background(), "task-feat", "build", opts)
+	if err != nil {
+		t.Fatalf("RunTask failed: %v\n%s", err, out.String())
+	}
+
+	data, _ := os.ReadFile(filepath.Join(mgr.Config.Features["task-feat"].Path, "order.txt"))
+	if got := strings.Fields(string(data)); len(got) != 2 || got[0] != "shared" || got[1] != "svc-a" {
+		t.Errorf("dependency order not respected: %v", got)
+	}
+	if len(report.Undefined) != 1 || report.Undefined[0] != "svc-b" {
+		t.Errorf("expected svc-b to be skipped as undefined: %v", report.Undefined)
+	}
+	if strings.Join(report.CriticalPath, ",") != "shared,svc-a" {
+		t.Errorf("unexpected critical path: %v", report.CriticalPath)
+	}
+
+	report, err = mgr.RunTask(context.Background(), "task-feat", "broken", opts)
+	if err == nil {
+		t.Fatal("broken task should fail")
+	}
+	for _, r := range report.Results {
+		if r.Repo == "svc-a" && !r.Skipped {
+			t.Errorf("svc-a should be skipped after its dependency failed: %+v", r)
+		}
+		if r.Repo == "svc-b" && r.ExitCode != 3 {
+			t.Errorf("svc-b should report exit code 3: %+v", r)
+		}
+	}
+
+	if _, err := mgr.RunTask(context.Background(), "task-feat", "cyclic", opts); err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Errorf("expected a cycle error, got %v", err)
+	}
+	if _, err := mgr.RunTask(context.Background(), "task-feat", "missing", opts); err == nil {
+		t.Error("expected an error for an undefined task")
+	}
+}
@@ -0,0 +1,82 @@
+package tests
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/vedantprajapati/Grove/internal/manager"
+)
+
+func TestWatchRecordsStateAndRunsHook(t *testing.T) {
+	mgr, remotesDir := setupFeature(t, "watch-feat", "svc-a", "svc-b")
+
+	opts := manager.WatchOptions{Once: true}
+	if err := mgr.Watch(context.Background(), opts); err != nil {
+		t.Fatalf("first refresh failed: %v", err)
+	}
+
+	statuses, _, err := mgr.CachedFeatureStatus("watch-feat")
+	if err != nil {
+		t.Fatalf("CachedFeatureStatus failed: %v", err)
+	}
+	if len(statuses) != 2 || statuses[0].Name != "svc-a" || statuses[1].Name != "svc-b" {
+		t.Fatalf("unexpected cached statuses: %+v", statuses)
+	}
+
+	// Move the base branch of one remote and refresh again.
+	remote := filepath.Join(remotesDir, "svc-a")
+	os.WriteFile(filepath.Join(remote, "CHANGE.md"), []byte("change"), 0644)
+	execGit(t, remote, "add", ".")
+	execGit(t, remote, "commit", "-m", "Move main")
+
+	hookOut := filepath.Join(t.TempDir(), "events.txt")
+	opts.OnChange = `echo "$GROVE_EVENT $GROVE_REPO" >> "` + filepath.ToSlash(hookOut) + `"`
+	if err := mgr.Watch(context.Background(), opts); err != nil {
+		t.Fatalf("second refresh failed: %v", err)
+	}
+
+	data, err := os.ReadFile(hookOut)
+	if err != nil {
+		t.Fatalf("hook did not run: %v", err)
+	}
+	if got := strings.TrimSpace(string(data)); got != "base svc-a" {
+		t.Errorf("expected a single base event for svc-a, got %q", got)
+	}
+}
+
+func TestWatchReloadsConfig(t *testing.T) {
+	mgr, _ := setupFeature(t, "watch-old", "svc-a")
+	if err := mgr.Watch(context.Background(), manager.WatchOptions{Once: true}); err != nil {
+		t.Fatalf("first refresh failed: %v", err)
+	}
+
+	// Another process swaps the feature for a new one while watch runs.
+	other, err := manager.NewManagerWithConfig(mgr.Config.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	other.CacheDir, other.DataDir = mgr.CacheDir, mgr.DataDir
+	if err := other.CreateFeature("test-set", "watch-new"); err != nil {
+		t.Fatalf("CreateFeature failed: %v", err)
+	}
+	if err := other.RemoveFeature("watch-old"); err != nil {
+		t.Fatalf("RemoveFeature failed: %v", err)
+	}
+
+	if err := mgr.Watch(context.Background(), manager.WatchOptions{Once: true}); err != nil {
+		t.Fatalf("second refresh failed: %v", err)
+	}
+	state, err := mgr.LoadWatchState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := state.Features["watch-new"]; !ok {
+		t.Error("expected the new feature to be watched")
+	}
+	if _, ok := state.Features["watch-old"]; ok {
+		t.Error("expected the removed feature to no longer be watched")
+	}
+}